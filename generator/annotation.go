@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// ServiceMarker - метка интерфейса, для которого генерируется сервис
+const ServiceMarker = "servicegen:service"
+
+// NATSMarker - директива метода с настройками темы и группы очереди NATS
+const NATSMarker = "servicegen:nats"
+
+// KafkaMarker - директива метода с топиками запросов и событий Kafka
+const KafkaMarker = "servicegen:kafka"
+
+// directivePrefix - начало меток всех директив servicegen
+const directivePrefix = "servicegen:"
+
+// methodMarkers - директивы, которые допустимы в комментарии метода
+var methodMarkers = map[string]bool{
+	NATSMarker:     true,
+	KafkaMarker:    true,
+	JSONMarker:     true,
+	ValidateMarker: true,
+}
+
+// Annotation - разобранные опции комментария //servicegen:service http nats logging
+type Annotation struct {
+	Options map[string]string // Флаги хранятся с пустым значением, опции вида key=value - со значением
+}
+
+// ParseAnnotation разбирает строку комментария, ok=false если метки сервиса нет
+func ParseAnnotation(text string) (Annotation, bool) {
+	return ParseDirective(text, ServiceMarker)
+}
+
+// ParseDirective разбирает опции комментария вида //marker a b=c, ok=false если метки нет
+func ParseDirective(text string, marker string) (Annotation, bool) {
+	rest, ok := directiveText(text, marker)
+	if !ok {
+		return Annotation{}, false
+	}
+	return parseOptions(rest), true
+}
+
+// directiveText возвращает текст комментария после marker, ok=false если метки нет.
+// За меткой должен идти пробел или конец строки: //servicegen:natsfoo - не директива nats
+func directiveText(text string, marker string) (string, bool) {
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], marker)
+		if idx < 0 {
+			return "", false
+		}
+		end := offset + idx + len(marker)
+		if end == len(text) || unicode.IsSpace(rune(text[end])) {
+			return text[end:], true
+		}
+		offset = end
+	}
+}
+
+// parseOptions разбирает опции a b=c, разделённые пробелами
+func parseOptions(text string) Annotation {
+	a := Annotation{Options: map[string]string{}}
+	for _, field := range strings.Fields(text) {
+		key, value, _ := strings.Cut(field, "=")
+		a.Options[key] = value
+	}
+	return a
+}
+
+// checkMethodDirectives проверяет, что директивы servicegen в комментарии метода известны
+func checkMethodDirectives(method *ast.Field) error {
+	if method.Doc == nil {
+		return nil
+	}
+	for _, comment := range method.Doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, directivePrefix) {
+			continue
+		}
+		name, _, _ := strings.Cut(text, " ")
+		if !methodMarkers[name] {
+			return fmt.Errorf("unknown directive %q", name)
+		}
+	}
+	return nil
+}
+
+// Has сообщает, указана ли опция
+func (a Annotation) Has(name string) bool {
+	_, ok := a.Options[name]
+	return ok
+}
+
+// Value возвращает значение опции key=value
+func (a Annotation) Value(name string) string {
+	return a.Options[name]
+}
+
+// methodDirective ищет директиву marker в комментарии метода
+func methodDirective(method *ast.Field, marker string) (Annotation, bool) {
+	if method.Doc == nil {
+		return Annotation{}, false
+	}
+	for _, comment := range method.Doc.List {
+		if a, ok := ParseDirective(comment.Text, marker); ok {
+			return a, true
+		}
+	}
+	return Annotation{}, false
+}
@@ -0,0 +1,40 @@
+package generator_test
+
+import (
+	"github.com/pablogolobaro/servicegen/generator"
+	"testing"
+)
+
+func TestParseDirective(t *testing.T) {
+	for _, tc := range []struct {
+		text    string
+		ok      bool
+		options map[string]string
+	}{
+		{"//servicegen:nats", true, map[string]string{}},
+		{"//servicegen:nats subject=a.b queue=-", true, map[string]string{"subject": "a.b", "queue": "-"}},
+		{"//servicegen:nats\tjetstream", true, map[string]string{"jetstream": ""}},
+		{"//servicegen:natsfoo subject=a.b", false, nil},
+		{"//servicegen:natsfoo //servicegen:nats jetstream", true, map[string]string{"jetstream": ""}},
+		{"//servicegen:kafka", false, nil},
+	} {
+		a, ok := generator.ParseDirective(tc.text, generator.NATSMarker)
+		if ok != tc.ok {
+			t.Errorf("%q: want ok %v, got %v", tc.text, tc.ok, ok)
+			continue
+		}
+		if len(a.Options) != len(tc.options) {
+			t.Errorf("%q: want options %v, got %v", tc.text, tc.options, a.Options)
+			continue
+		}
+		for key, value := range tc.options {
+			if got, ok := a.Options[key]; !ok || got != value {
+				t.Errorf("%q: want %s=%s, got %v", tc.text, key, value, a.Options)
+			}
+		}
+	}
+
+	if _, ok := generator.ParseAnnotation("//servicegen:servicex http"); ok {
+		t.Error("want //servicegen:servicex not to be a service annotation")
+	}
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"github.com/pablogolobaro/servicegen/templates"
+	"path/filepath"
+)
+
+// Встроенные артефакты
+func init() {
+	Register(FileArtifact{ID: "implementation", Package: ImplementationPackage, Dir: ImplementationPackage, File: ImplementationPackage, Template: templates.ImplementationTemplate})
+	Register(FileArtifact{ID: "transport", Package: TransportPackage, Dir: TransportPackage, File: TransportPackage, Template: templates.TransportTemplate})
+	Register(FileArtifact{ID: "root", Package: CmdPackage, Dir: CmdPackage, File: RootFilename, Template: templates.RootTemplate})
+	Register(FileArtifact{ID: "config", Package: ConfigPackage, Dir: ConfigPackage, File: ConfigPackage, Template: templates.ConfigTemplate})
+	Register(FileArtifact{ID: "otel", Package: OtelTracingPackage, Dir: OtelTracingPackage, File: OtelTracingPackage, Template: templates.TracingTemplate})
+	Register(FileArtifact{ID: "error", File: ErrorFileName, Template: templates.ErrorTemplate})
+	Register(FileArtifact{ID: "clientcmd", Package: CmdPackage, Dir: CmdPackage, File: ClientCmdFilename, Template: templates.ClientCmdTemplate, When: cliClient})
+	Register(FileArtifact{ID: "clientcmdtest", Package: CmdPackage, Dir: CmdPackage, File: ClientCmdTestFileName, Template: templates.ClientCmdTestTemplate, When: cliStreamTests})
+	Register(FileArtifact{ID: "service", Package: CmdPackage, Dir: CmdPackage, File: ServiceFileName, Template: templates.ServiceTemplate, When: runnable})
+
+	Register(FileArtifact{ID: "http", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: HttpFileName, Template: templates.HttpTemplate, Option: "http"})
+	Register(FileArtifact{ID: "httpclient", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: HttpClientFileName, Template: templates.HttpClientTemplate, Option: "http"})
+	Register(FileArtifact{ID: "httptest", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: HttpTestFileName, Template: templates.HttpTestTemplate, When: transportTests("http")})
+	Register(FileArtifact{ID: "httpstreamtest", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: HttpStreamTestFileName, Template: templates.HttpStreamTestTemplate, When: httpStreams})
+	Register(FileArtifact{ID: "openapi.yaml", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: OpenAPIYAMLFileName, Template: templates.OpenAPIYAMLTemplate, Option: "http"})
+	Register(FileArtifact{ID: "openapi.json", Package: HttpPackage, Dir: filepath.Join(TransportPackage, HttpPackage), File: OpenAPIJSONFileName, Template: templates.OpenAPIJSONTemplate, Option: "http"})
+	Register(FileArtifact{ID: "httprun", Package: CmdPackage, Dir: CmdPackage, File: HttpRunFilename, Template: templates.HttpRunTemplate, When: httpServer})
+	Register(FileArtifact{ID: "proto", Package: ProtoPackage, Dir: filepath.Join(TransportPackage, GrpcPackage, ProtoPackage), File: ProtoFileName, Template: templates.ProtoTemplate, Option: "grpc"})
+	Register(FileArtifact{ID: "grpc", Package: GrpcPackage, Dir: filepath.Join(TransportPackage, GrpcPackage), File: GrpcFileName, Template: templates.GrpcTemplate, Option: "grpc"})
+	Register(FileArtifact{ID: "grpctest", Package: GrpcPackage, Dir: filepath.Join(TransportPackage, GrpcPackage), File: GrpcTestFileName, Template: templates.GrpcTestTemplate, When: transportTests("grpc")})
+	Register(FileArtifact{ID: "grpcrun", Package: CmdPackage, Dir: CmdPackage, File: GrpcRunFilename, Template: templates.GrpcRunTemplate, Option: "grpc"})
+	Register(FileArtifact{ID: "nats", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: NatsFileName, Template: templates.NatsTemplate, Option: "nats"})
+	Register(FileArtifact{ID: "natsclient", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: NatsClientFileName, Template: templates.NatsClientTemplate, Option: "nats"})
+	Register(FileArtifact{ID: "natsclienttest", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: NatsClientTestFileName, Template: templates.NatsClientTestTemplate, When: transportTests("nats")})
+	Register(FileArtifact{ID: "jetstream", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: JetStreamFileName, Template: templates.JetStreamTemplate, When: ServiceGenerator.usesJetStream})
+	Register(FileArtifact{ID: "jetstreamtest", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: JetStreamTestFileName, Template: templates.JetStreamTestTemplate, When: jetStreamTests})
+	Register(FileArtifact{ID: "asyncapi.yaml", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: AsyncAPIYAMLFileName, Template: templates.AsyncAPIYAMLTemplate, Option: "nats"})
+	Register(FileArtifact{ID: "asyncapi.json", Package: NatsPackage, Dir: filepath.Join(TransportPackage, NatsPackage), File: AsyncAPIJSONFileName, Template: templates.AsyncAPIJSONTemplate, Option: "nats"})
+	Register(FileArtifact{ID: "kafka", Package: KafkaPackage, Dir: filepath.Join(TransportPackage, KafkaPackage), File: KafkaFileName, Template: templates.KafkaTemplate, Option: "kafka"})
+	Register(FileArtifact{ID: "kafkatest", Package: KafkaPackage, Dir: filepath.Join(TransportPackage, KafkaPackage), File: KafkaTestFileName, Template: templates.KafkaTestTemplate, When: transportTests("kafka")})
+	Register(FileArtifact{ID: "amqp", Package: AmqpPackage, Dir: filepath.Join(TransportPackage, AmqpPackage), File: AmqpFileName, Template: templates.AmqpTemplate, Option: "amqp"})
+	Register(FileArtifact{ID: "amqpclient", Package: AmqpPackage, Dir: filepath.Join(TransportPackage, AmqpPackage), File: AmqpClientFileName, Template: templates.AmqpClientTemplate, Option: "amqp"})
+	Register(FileArtifact{ID: "amqptest", Package: AmqpPackage, Dir: filepath.Join(TransportPackage, AmqpPackage), File: AmqpTestFileName, Template: templates.AmqpTestTemplate, When: transportTests("amqp")})
+	Register(FileArtifact{ID: "jsonrpc", Package: JsonRpcPackage, Dir: filepath.Join(TransportPackage, JsonRpcPackage), File: JsonRpcFileName, Template: templates.JsonRpcTemplate, Option: "jsonrpc"})
+	Register(FileArtifact{ID: "jsonrpcclient", Package: JsonRpcPackage, Dir: filepath.Join(TransportPackage, JsonRpcPackage), File: JsonRpcClientFileName, Template: templates.JsonRpcClientTemplate, Option: "jsonrpc"})
+	Register(FileArtifact{ID: "jsonrpctest", Package: JsonRpcPackage, Dir: filepath.Join(TransportPackage, JsonRpcPackage), File: JsonRpcTestFileName, Template: templates.JsonRpcTestTemplate, When: transportTests("jsonrpc")})
+	Register(FileArtifact{ID: "logging", Package: MiddlewarePackage, Dir: MiddlewarePackage, File: LoggingFileName, Template: templates.LoggingTemplate, Option: "logging"})
+	Register(FileArtifact{ID: "tracing", Package: MiddlewarePackage, Dir: MiddlewarePackage, File: TracingFileName, Template: templates.InstrumentationTemplate, Option: "tracing"})
+}
+
+// runnable - у сервиса есть команда запуска сервера, которой нужна общая сборка сервиса
+func runnable(r ServiceGenerator) bool {
+	return httpServer(r) || r.Annotation.Has("grpc")
+}
+
+// httpServer - команда httprun поднимает echo сервер для REST маршрутов и JSON-RPC
+func httpServer(r ServiceGenerator) bool {
+	return r.Annotation.Has("http") || r.Annotation.Has("jsonrpc")
+}
+
+// generatesTests - тесты транспортов генерируются в пакеты сервиса только по опции аннотации tests
+// или настройке tests
+func (r ServiceGenerator) generatesTests() bool {
+	return r.Annotation.Has("tests") || r.Config.Tests
+}
+
+// transportTests - условие тестов транспорта, включённого опцией option
+func transportTests(option string) func(ServiceGenerator) bool {
+	return func(r ServiceGenerator) bool {
+		return r.Annotation.Has(option) && r.generatesTests()
+	}
+}
+
+// httpStreams - потоковые методы HTTP транспорта проверяются сгенерированными тестами
+func httpStreams(r ServiceGenerator) bool {
+	return r.Annotation.Has("http") && r.usesStreams() && r.generatesTests()
+}
+
+// jetStreamTests - consumers JetStream проверяются сгенерированными тестами
+func jetStreamTests(r ServiceGenerator) bool {
+	return r.usesJetStream() && r.generatesTests()
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// asyncAPIDocument - документ AsyncAPI 3.0 NATS транспорта
+type asyncAPIDocument struct {
+	AsyncAPI           string                        `json:"asyncapi" yaml:"asyncapi"`
+	Info               asyncAPIInfo                  `json:"info" yaml:"info"`
+	DefaultContentType string                        `json:"defaultContentType" yaml:"defaultContentType"`
+	Channels           map[string]*asyncAPIChannel   `json:"channels" yaml:"channels"`
+	Operations         map[string]*asyncAPIOperation `json:"operations" yaml:"operations"`
+	Components         asyncAPIComponents            `json:"components" yaml:"components"`
+}
+
+type asyncAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type asyncAPIChannel struct {
+	// Address - тема NATS, nil для inbox ответа, который выбирает отправитель запроса
+	Address     *string                 `json:"address" yaml:"address"`
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Messages    map[string]*asyncAPIRef `json:"messages" yaml:"messages"`
+}
+
+type asyncAPIOperation struct {
+	Action      string            `json:"action" yaml:"action"`
+	Channel     asyncAPIRef       `json:"channel" yaml:"channel"`
+	Summary     string            `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Bindings    *asyncAPIBindings `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+	Messages    []asyncAPIRef     `json:"messages" yaml:"messages"`
+	Reply       *asyncAPIReply    `json:"reply,omitempty" yaml:"reply,omitempty"`
+}
+
+type asyncAPIReply struct {
+	Channel  asyncAPIRef   `json:"channel" yaml:"channel"`
+	Messages []asyncAPIRef `json:"messages" yaml:"messages"`
+}
+
+type asyncAPIBindings struct {
+	NATS asyncAPINATSBinding `json:"nats" yaml:"nats"`
+}
+
+type asyncAPINATSBinding struct {
+	Queue          string `json:"queue,omitempty" yaml:"queue,omitempty"`
+	BindingVersion string `json:"bindingVersion" yaml:"bindingVersion"`
+}
+
+type asyncAPIRef struct {
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+type asyncAPIMessage struct {
+	Name    string  `json:"name" yaml:"name"`
+	Title   string  `json:"title,omitempty" yaml:"title,omitempty"`
+	Payload *Schema `json:"payload" yaml:"payload"`
+}
+
+type asyncAPIComponents struct {
+	Messages map[string]*asyncAPIMessage `json:"messages" yaml:"messages"`
+	Schemas  map[string]*Schema          `json:"schemas" yaml:"schemas"`
+}
+
+const (
+	asyncAPIVersion        = "3.0.0"
+	asyncAPINATSVersion    = "0.1.0"
+	asyncAPIMessagePrefix  = "#/components/messages/"
+	genericErrorResponse   = "GenericErrorResponse"
+	asyncAPIReplySuffix    = "Reply"
+	asyncAPIValueSuffix    = "Value"
+	asyncAPIChannelsPrefix = "#/channels/"
+)
+
+// AsyncAPI строит документ NATS транспорта из той же модели, что и RegisterSubscribers:
+// тема и группа очереди каждого метода, запрос, ответ и конверт ошибки
+func (p templateParams) AsyncAPI() (*asyncAPIDocument, error) {
+	s, err := p.generator.newSchemas(schemaRefPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("asyncapi: %v", err)
+	}
+	s.envelope()
+
+	doc := &asyncAPIDocument{
+		AsyncAPI: asyncAPIVersion,
+		Info: asyncAPIInfo{
+			Title:       p.ServiceName + " NATS API",
+			Version:     "v1",
+			Description: "Request-reply subjects of " + p.ServicePackage + "." + p.ServiceName + ". Replies are sent to the inbox of the request.",
+		},
+		DefaultContentType: jsonContentType,
+		Channels:           map[string]*asyncAPIChannel{},
+		Operations:         map[string]*asyncAPIOperation{},
+		Components: asyncAPIComponents{
+			Messages: map[string]*asyncAPIMessage{
+				genericErrorResponse: {
+					Name:    genericErrorResponse,
+					Title:   "Error envelope sent instead of the response on transport and business errors",
+					Payload: &Schema{Ref: schemaRefPrefix + genericErrorResponse},
+				},
+			},
+		},
+	}
+
+	for _, function := range p.Functions {
+		request, response, err := s.messages(function)
+		if err != nil {
+			return nil, fmt.Errorf("asyncapi: method %s: %v", function.Name, err)
+		}
+		requestName := function.Name + "Request"
+		responseName := function.Name + "Response"
+		replyChannel := function.Name + asyncAPIReplySuffix
+		s.Components[requestName] = request
+
+		doc.Components.Messages[requestName] = &asyncAPIMessage{Name: requestName, Payload: &Schema{Ref: schemaRefPrefix + requestName}}
+
+		subject := function.NATSSubject
+		doc.Channels[function.Name] = &asyncAPIChannel{
+			Address:     &subject,
+			Description: function.Doc,
+			Messages: map[string]*asyncAPIRef{
+				requestName: {Ref: asyncAPIMessagePrefix + requestName},
+			},
+		}
+		operation := &asyncAPIOperation{
+			Action:      "receive",
+			Channel:     asyncAPIRef{Ref: asyncAPIChannelsPrefix + function.Name},
+			Summary:     summary(function.Doc),
+			Description: function.Doc,
+			Bindings:    &asyncAPIBindings{NATS: asyncAPINATSBinding{Queue: function.NATSQueue, BindingVersion: asyncAPINATSVersion}},
+			Messages:    []asyncAPIRef{{Ref: asyncAPIChannelsPrefix + function.Name + "/messages/" + requestName}},
+		}
+		doc.Operations[function.Name] = operation
+		if function.JetStream {
+			//Запрос сохраняется в потоке и обрабатывается durable consumer, ответа нет
+			operation.Bindings = nil
+			operation.Description = strings.TrimSpace(function.Doc + "\n\nDelivered through the JetStream stream " + p.JetStreamName() + " to the durable consumer " + p.JetStreamDurable(function.Name) + ".")
+			continue
+		}
+
+		s.Components[responseName] = response
+		doc.Components.Messages[responseName] = &asyncAPIMessage{Name: responseName, Payload: &Schema{Ref: schemaRefPrefix + responseName}}
+		doc.Channels[replyChannel] = &asyncAPIChannel{
+			Description: "Reply inbox of the " + function.Name + " request",
+			Messages: map[string]*asyncAPIRef{
+				responseName:         {Ref: asyncAPIMessagePrefix + responseName},
+				genericErrorResponse: {Ref: asyncAPIMessagePrefix + genericErrorResponse},
+			},
+		}
+
+		operation.Reply = &asyncAPIReply{
+			Channel: asyncAPIRef{Ref: asyncAPIChannelsPrefix + replyChannel},
+			Messages: []asyncAPIRef{
+				{Ref: asyncAPIChannelsPrefix + replyChannel + "/messages/" + responseName},
+				{Ref: asyncAPIChannelsPrefix + replyChannel + "/messages/" + genericErrorResponse},
+			},
+		}
+		if function.Stream {
+			//За ответом в inbox идут значения канала по одному в сообщении, пустое сообщение завершает поток
+			value, err := s.Of(function.StreamType)
+			if err != nil {
+				return nil, fmt.Errorf("asyncapi: method %s: %v", function.Name, err)
+			}
+			valueName := function.Name + asyncAPIValueSuffix
+			doc.Components.Messages[valueName] = &asyncAPIMessage{Name: valueName, Title: "Value of the " + function.Name + " stream", Payload: value}
+			doc.Channels[replyChannel].Messages[valueName] = &asyncAPIRef{Ref: asyncAPIMessagePrefix + valueName}
+			operation.Reply.Messages = append(operation.Reply.Messages, asyncAPIRef{Ref: asyncAPIChannelsPrefix + replyChannel + "/messages/" + valueName})
+			operation.Description = strings.TrimSpace(function.Doc + "\n\nThe response is followed by a " + valueName + " message per value and an empty message at the end of the stream. " +
+				"A message to the reply inbox with the suffix .cancel stops the stream.")
+		}
+	}
+
+	doc.Components.Schemas = s.Components
+	return doc, nil
+}
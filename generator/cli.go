@@ -0,0 +1,87 @@
+package generator
+
+import "strings"
+
+// pflagFunc - функция pflag для поля запроса и её нулевое значение
+type pflagFunc struct {
+	Func string
+	Zero string
+}
+
+// pflagFuncs - типы полей запроса, у которых есть флаг pflag,
+// остальные типы команда client принимает в JSON
+var pflagFuncs = map[string]pflagFunc{
+	"string":          {"StringVar", `""`},
+	"bool":            {"BoolVar", "false"},
+	"int":             {"IntVar", "0"},
+	"int8":            {"Int8Var", "0"},
+	"int16":           {"Int16Var", "0"},
+	"int32":           {"Int32Var", "0"},
+	"int64":           {"Int64Var", "0"},
+	"uint":            {"UintVar", "0"},
+	"uint8":           {"Uint8Var", "0"},
+	"uint16":          {"Uint16Var", "0"},
+	"uint32":          {"Uint32Var", "0"},
+	"uint64":          {"Uint64Var", "0"},
+	"float32":         {"Float32Var", "0"},
+	"float64":         {"Float64Var", "0"},
+	"time.Duration":   {"DurationVar", "0"},
+	"[]string":        {"StringSliceVar", "nil"},
+	"[]bool":          {"BoolSliceVar", "nil"},
+	"[]int":           {"IntSliceVar", "nil"},
+	"[]int32":         {"Int32SliceVar", "nil"},
+	"[]int64":         {"Int64SliceVar", "nil"},
+	"[]uint":          {"UintSliceVar", "nil"},
+	"[]float32":       {"Float32SliceVar", "nil"},
+	"[]float64":       {"Float64SliceVar", "nil"},
+	"[]time.Duration": {"DurationSliceVar", "nil"},
+}
+
+// cliFlags - флаги команды client и корневой команды, флаг поля запроса с таким именем получает суффикс
+var cliFlags = map[string]bool{
+	"transport": true,
+	"addr":      true,
+	"output":    true,
+	"timeout":   true,
+	"config":    true,
+	"trace":     true,
+	"help":      true,
+}
+
+// FlagName - имя флага поля запроса в команде client
+func (p parameter) FlagName() string {
+	name := strings.ToLower(p.Field)
+	if cliFlags[name] {
+		name += "-arg"
+	}
+	return name
+}
+
+// FlagFunc - функция pflag, которой команда client объявляет флаг поля запроса,
+// пустая строка - значение флага передаётся в JSON
+func (p parameter) FlagFunc() string {
+	return pflagFuncs[p.Type].Func
+}
+
+// FlagZero - значение флага поля запроса по умолчанию
+func (p parameter) FlagZero() string {
+	return pflagFuncs[p.Type].Zero
+}
+
+// Summary - первое предложение комментария метода, описание команды client
+func (f ServiceFunction) Summary() string {
+	if f.Doc == "" {
+		return "Call " + f.Name
+	}
+	return summary(f.Doc)
+}
+
+// cliClient - команду client можно собрать, если у сервиса есть HTTP или NATS клиент
+func cliClient(r ServiceGenerator) bool {
+	return r.Annotation.Has("http") || r.Annotation.Has("nats")
+}
+
+// cliStreamTests - потоковые методы команды client проверяются сгенерированными тестами через HTTP клиент
+func cliStreamTests(r ServiceGenerator) bool {
+	return r.Annotation.Has("http") && r.usesStreams() && r.generatesTests()
+}
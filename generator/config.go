@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName - файл настроек в корне модуля
+const ConfigFileName = "servicegen.yaml"
+
+// Config - настройки генерации, общие для всех сервисов
+type Config struct {
+	Module    string        `yaml:"module"`    // Имя модуля, по умолчанию из go.mod
+	Templates string        `yaml:"templates"` // Каталог шаблонов проекта, по умолчанию <module root>/.servicegen/templates
+	Verify    bool          `yaml:"verify"`    // Проверять типы сгенерированного кода перед записью
+	Tests     bool          `yaml:"tests"`     // Генерировать тесты транспортов в пакеты сервиса
+	NATS      NATSConfig    `yaml:"nats"`      // Темы и группы очередей NATS
+	Kafka     KafkaConfig   `yaml:"kafka"`     // Топики, группа потребителей и фиксация смещений Kafka
+	JSONRPC   JSONRPCConfig `yaml:"jsonrpc"`   // Путь JSON-RPC на HTTP сервере
+	HTTP      HTTPConfig    `yaml:"http"`      // Роутер, ответы и статусы ошибок HTTP транспорта
+	JSON      JSONConfig    `yaml:"json"`      // Имена JSON полей запросов и результатов
+	Errors    []DomainError `yaml:"errors"`    // Ошибки-сигналы сервисов, блок //servicegen:errors дополняет их
+}
+
+// NATSConfig - именование тем NATS. Опции аннотации сервиса nats.subject, nats.version
+// и nats.queue перекрывают настройки, директива метода //servicegen:nats subject=... queue=... - и их
+type NATSConfig struct {
+	Subject string `yaml:"subject"` // Шаблон темы с {service}, {version} и {method}, по умолчанию {service}.{version}.{method}
+	Version string `yaml:"version"` // Версия в теме, по умолчанию v1
+	Queue   string `yaml:"queue"`   // Группа очереди, по умолчанию имя сервиса, "-" - без группы
+}
+
+// KafkaConfig - настройки транспорта Kafka. Опции аннотации сервиса kafka.topic, kafka.events,
+// kafka.version, kafka.group и kafka.commit перекрывают настройки, директива метода
+// //servicegen:kafka topic=... events=... - и их
+type KafkaConfig struct {
+	Topic   string `yaml:"topic"`   // Шаблон топика запросов с {service}, {version} и {method}, по умолчанию {service}.{version}.{method}
+	Events  string `yaml:"events"`  // Шаблон топика событий, по умолчанию {service}.{version}.{method}.events, "-" - без событий
+	Version string `yaml:"version"` // Версия в топике, по умолчанию v1
+	Group   string `yaml:"group"`   // Группа потребителей, по умолчанию имя сервиса
+	Commit  string `yaml:"commit"`  // Фиксация смещений: after (по умолчанию), before или sync
+}
+
+// JSONRPCConfig - настройки JSON-RPC транспорта, опция аннотации jsonrpc.path перекрывает их
+type JSONRPCConfig struct {
+	Path string `yaml:"path"` // Путь на HTTP сервере, по умолчанию /rpc
+}
+
+// HTTPConfig - настройки HTTP транспорта, опции аннотации router, http.errors, http.response
+// и http.status.<ключ> перекрывают их
+type HTTPConfig struct {
+	Router   string         `yaml:"router"`   // echo (по умолчанию), chi, stdlib или gin
+	Status   map[string]int `yaml:"status"`   // Статусы ответов по коду AppError или имени ошибки-сигнала, остальные ошибки - 500
+	Errors   string         `yaml:"errors"`   // Формат ответов с ошибкой: envelope (по умолчанию) или problem
+	Response string         `yaml:"response"` // Успешный ответ: envelope (по умолчанию) или bare - только результат
+}
+
+// JSONConfig - имена JSON полей запросов и результатов, опции аннотации json.naming и json.omitempty
+// перекрывают настройки, директива метода //servicegen:json <аргумент>=<имя>,omitempty - и их
+type JSONConfig struct {
+	Naming    string `yaml:"naming"`    // lower (по умолчанию), camel, snake или as-is
+	OmitEmpty bool   `yaml:"omitempty"` // Не передавать пустые значения полей
+}
+
+// LoadConfig читает настройки из YAML файла
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %v", err)
+	}
+
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %v", path, err)
+	}
+	return cfg, nil
+}
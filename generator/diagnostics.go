@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SeverityError - единственный пока уровень диагностики
+const SeverityError = "error"
+
+// Diagnostic - сообщение генератора, привязанное к позиции в файле
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// String форматирует сообщение как компилятор: service.go:12:2: method Erase: unsupported result type chan int
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	if d.File != "" {
+		b.WriteString(d.File)
+		if d.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", d.Line, d.Column)
+		}
+		b.WriteString(": ")
+	}
+	if d.Method != "" {
+		fmt.Fprintf(&b, "method %s: ", d.Method)
+	}
+	b.WriteString(d.Message)
+	return b.String()
+}
+
+// Diagnostics - все сообщения одного запуска, используется как ошибка
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, 0, len(d))
+	for _, diagnostic := range d {
+		lines = append(lines, diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteJSON печатает сообщения массивом JSON для интеграции с редакторами
+func (d Diagnostics) WriteJSON(w io.Writer) error {
+	if d == nil {
+		d = Diagnostics{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(d)
+}
+
+// newDiagnostic создаёт сообщение в позиции pos
+func newDiagnostic(pos token.Position, method string, message string) Diagnostic {
+	return Diagnostic{
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Method:   method,
+		Severity: SeverityError,
+		Message:  message,
+	}
+}
+
+// toDiagnostics превращает ошибку в сообщения, file используется для ошибок без позиции
+func toDiagnostics(err error, file string) Diagnostics {
+	var diagnostics Diagnostics
+	if errors.As(err, &diagnostics) {
+		return diagnostics
+	}
+
+	var list scanner.ErrorList
+	if errors.As(err, &list) {
+		for _, e := range list {
+			diagnostics = append(diagnostics, newDiagnostic(e.Pos, "", e.Msg))
+		}
+		return diagnostics
+	}
+
+	return Diagnostics{newDiagnostic(token.Position{Filename: file}, "", err.Error())}
+}
+
+// parsePosition разбирает позицию вида file.go:12:2
+func parsePosition(pos string) token.Position {
+	var ret token.Position
+
+	idx := strings.LastIndex(pos, ":")
+	if idx < 0 {
+		return token.Position{Filename: pos}
+	}
+	column, err := strconv.Atoi(pos[idx+1:])
+	if err != nil {
+		return token.Position{Filename: pos}
+	}
+
+	rest := pos[:idx]
+	idx = strings.LastIndex(rest, ":")
+	if idx < 0 {
+		return token.Position{Filename: pos}
+	}
+	line, err := strconv.Atoi(rest[idx+1:])
+	if err != nil {
+		//Позиция без колонки file.go:12
+		return token.Position{Filename: rest, Line: column}
+	}
+
+	ret.Filename = rest[:idx]
+	ret.Line = line
+	ret.Column = column
+	return ret
+}
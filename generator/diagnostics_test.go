@@ -0,0 +1,212 @@
+package generator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pablogolobaro/servicegen/generator"
+	"testing"
+)
+
+// diagnose generates the source and returns the diagnostics of the run
+func diagnose(t *testing.T, src string, config generator.Config) generator.Diagnostics {
+	t.Helper()
+
+	_, err := generator.Run(context.Background(), generator.Options{
+		Sources: []string{src},
+		Config:  config,
+	})
+
+	var diagnostics generator.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		t.Fatalf("want Diagnostics, got %v", err)
+	}
+	return diagnostics
+}
+
+func TestDiagnostics(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		src    string
+		config generator.Config
+		want   []string
+	}{
+		{
+			name: "signatures",
+			src:  "testdata/invalid/service.go",
+			want: []string{
+				"testdata/invalid/service.go:9:2: method Watch: unsupported result type chan Event",
+				"testdata/invalid/service.go:10:2: method Count: last result must be error",
+				"testdata/invalid/service.go:11:2: method Apply: unsupported argument type func(Event) bool",
+				"testdata/invalid/service.go:12:2: method Tags: variadic arguments are not supported",
+			},
+		},
+		{
+			name: "unknown directive",
+			src:  "testdata/unknowndirective/service.go",
+			want: []string{
+				`testdata/unknowndirective/service.go:8:2: method Place: unknown directive "servicegen:natsfoo"`,
+			},
+		},
+		{
+			name: "nats subjects",
+			src:  "testdata/natsconflict/service.go",
+			want: []string{
+				`testdata/natsconflict/service.go:9:2: method Run: NATS subject "jobs.run" is already used by method Start`,
+				`testdata/natsconflict/service.go:11:2: method Get: invalid NATS subject "jobs.{id}": unknown placeholder, use {service}, {version} or {method}`,
+				`testdata/natsconflict/service.go:13:2: method Count: JetStream methods must return only error`,
+			},
+		},
+		{
+			name: "kafka topics",
+			src:  "testdata/kafkaconflict/service.go",
+			want: []string{
+				`testdata/kafkaconflict/service.go:6:6: unknown Kafka commit strategy "never", use after, before or sync`,
+				`testdata/kafkaconflict/service.go:9:2: method Place: Kafka topic "orders.v1.place.events" is already used by method Create`,
+				`testdata/kafkaconflict/service.go:11:2: method Cancel: invalid Kafka topic "orders/cancel"`,
+			},
+		},
+		{
+			name: "jsonrpc path",
+			src:  "testdata/jsonrpcconflict/service.go",
+			want: []string{
+				`testdata/jsonrpcconflict/service.go:6:6: JSON-RPC path "/api/v1/rpc" overlaps the HTTP routes under /api/v1`,
+			},
+		},
+		{
+			name: "streams",
+			src:  "testdata/streamconflict/service.go",
+			want: []string{
+				"testdata/streamconflict/service.go:7:2: method Watch: streaming methods are not supported by the grpc transport",
+				"testdata/streamconflict/service.go:8:2: method Follow: streaming methods must return (<-chan T, error)",
+			},
+		},
+		{
+			name: "http router",
+			src:  "testdata/routerconflict/service.go",
+			want: []string{
+				`testdata/routerconflict/service.go:6:6: unknown HTTP router "mux", use echo, chi, stdlib, gin`,
+			},
+		},
+		{
+			name: "http errors",
+			src:  "testdata/errorsformat/service.go",
+			want: []string{
+				`testdata/errorsformat/service.go:6:6: unknown HTTP error format "xml", use envelope or problem`,
+			},
+		},
+		{
+			name: "json names",
+			src:  "testdata/jsonconflict/service.go",
+			want: []string{
+				`testdata/jsonconflict/service.go:8:2: method Place: json: name "name" of Name is already used by Id`,
+			},
+		},
+		{
+			name: "json sides",
+			src:  "testdata/jsonside/service.go",
+			want: []string{
+				`testdata/jsonside/service.go:10:2: method Find: json: name is both an argument and a result, use request.name or response.name`,
+			},
+		},
+		{
+			name: "json result",
+			src:  "testdata/jsonresult/service.go",
+			want: []string{
+				`testdata/jsonresult/service.go:8:2: method Count: json: result total is the only result and is sent as result, it has no tag`,
+			},
+		},
+		{
+			name: "validate",
+			src:  "testdata/validateconflict/service.go",
+			want: []string{
+				`testdata/validateconflict/service.go:8:2: method Place: validate: Count: rule min: invalid integer "one"`,
+			},
+		},
+		{
+			name: "validate range",
+			src:  "testdata/validaterange/service.go",
+			want: []string{
+				`testdata/validaterange/service.go:8:2: method Place: validate: Small: rule max: 1000 is out of range of int8`,
+				`testdata/validaterange/service.go:10:2: method Count: validate: Count: rule min: -1 is out of range of uint`,
+				`testdata/validaterange/service.go:12:2: method Rate: validate: Ratio: rule max: 1e40 is out of range of float32`,
+			},
+		},
+		{
+			name: "http status",
+			src:  "testdata/statusconflict/service.go",
+			want: []string{
+				`testdata/statusconflict/service.go:6:6: invalid HTTP status "302" of ErrNotFound, use 400-599`,
+			},
+		},
+		{
+			name: "unmapped status",
+			src:  "testdata/unmappedstatus/service.go",
+			want: []string{
+				`testdata/unmappedstatus/service.go:13:6: error ErrUnknown: code 444 is not an HTTP error status, set http= or http.status.ErrUnknown`,
+				`testdata/unmappedstatus/service.go:13:6: error ErrLow: code 7 is not an HTTP error status, set http= or http.status.ErrLow`,
+			},
+		},
+		{
+			name: "undeclared status",
+			src:  "testdata/undeclaredstatus/service.go",
+			config: generator.Config{
+				HTTP: generator.HTTPConfig{Status: map[string]int{"ErrConfig": 409}},
+			},
+			want: []string{
+				`testdata/undeclaredstatus/service.go:9:6: HTTP status of undeclared error ErrConfig, declare it in //servicegen:errors or the config`,
+				`testdata/undeclaredstatus/service.go:9:6: HTTP status of undeclared error ErrMissing, declare it in //servicegen:errors or the config`,
+			},
+		},
+		{
+			name: "errors",
+			src:  "testdata/errorsconflict/service.go",
+			config: generator.Config{
+				Errors: []generator.DomainError{{Name: "ErrConfig", Message: "declared in the config"}},
+			},
+			want: []string{
+				`testdata/errorsconflict/service.go:13:6: error ErrConfig: code is required`,
+				`testdata/errorsconflict/service.go:7:1: error ErrMissing is declared twice`,
+				`testdata/errorsconflict/service.go:8:1: error ErrGone: unknown gRPC code "Gone"`,
+				`testdata/errorsconflict/service.go:9:1: error ErrLost: message must be a quoted string`,
+				`testdata/errorsconflict/service.go:13:6: error ErrStale: code 404 is already used by ErrMissing`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diagnostics := diagnose(t, tc.src, tc.config)
+			if len(diagnostics) != len(tc.want) {
+				t.Fatalf("want %d diagnostics, got:\n%v", len(tc.want), diagnostics)
+			}
+			for i := range tc.want {
+				if got := diagnostics[i].String(); got != tc.want[i] {
+					t.Errorf("diagnostic %d:\nwant %s\ngot  %s", i, tc.want[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnosticsParseError(t *testing.T) {
+	diagnostics := diagnose(t, "testdata/invalid/missing.go", generator.Config{})
+	if len(diagnostics) != 1 || diagnostics[0].File != "testdata/invalid/missing.go" {
+		t.Errorf("want one diagnostic for missing file, got %v", diagnostics)
+	}
+}
+
+func TestDiagnosticsWriteJSON(t *testing.T) {
+	diagnostics := diagnose(t, "testdata/invalid/service.go", generator.Config{})
+
+	var buf bytes.Buffer
+	if err := diagnostics.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []generator.Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(diagnostics) || decoded[0] != diagnostics[0] {
+		t.Errorf("JSON round trip: want %+v, got %+v", diagnostics, decoded)
+	}
+}
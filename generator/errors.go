@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorsMarker - первая строка блока комментариев с ошибками сервиса:
+//
+//	//servicegen:errors
+//	//ErrNotFound "item not found" code=404 http=404 grpc=NotFound
+//	//ErrBusy "service is busy" code=503 retryable
+const ErrorsMarker = "servicegen:errors"
+
+// DomainError - ошибка-сигнал пакета сервиса, из которой генерируются переменная,
+// код AppError, признак повтора и статусы транспортов
+type DomainError struct {
+	Name      string `yaml:"name"`      // Имя переменной, ErrNotFound
+	Message   string `yaml:"message"`   // Текст ошибки
+	Code      int    `yaml:"code"`      // Код AppError
+	Retryable bool   `yaml:"retryable"` // Повтор вызова может пройти успешно
+	HTTP      int    `yaml:"http"`      // Статус HTTP ответа, 0 - по коду AppError
+	GRPC      string `yaml:"grpc"`      // Код статуса gRPC, пустой - ошибка передаётся в поле Error ответа
+}
+
+// grpcCodes - имена кодов статуса google.golang.org/grpc/codes, кроме OK
+var grpcCodes = map[string]bool{
+	"Canceled":           true,
+	"Unknown":            true,
+	"InvalidArgument":    true,
+	"DeadlineExceeded":   true,
+	"NotFound":           true,
+	"AlreadyExists":      true,
+	"PermissionDenied":   true,
+	"ResourceExhausted":  true,
+	"FailedPrecondition": true,
+	"Aborted":            true,
+	"OutOfRange":         true,
+	"Unimplemented":      true,
+	"Internal":           true,
+	"Unavailable":        true,
+	"DataLoss":           true,
+	"Unauthenticated":    true,
+}
+
+// Slug - имя ошибки без Err через дефис, ErrNotFound - not-found
+func (e DomainError) Slug() string {
+	return strings.Join(words(strings.TrimPrefix(e.Name, "Err")), "-")
+}
+
+// check проверяет объявление ошибки
+func (e DomainError) check() error {
+	if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+		return fmt.Errorf("invalid error name %q, use an exported identifier", e.Name)
+	}
+	if e.Message == "" {
+		return fmt.Errorf("error %s: message is required", e.Name)
+	}
+	if e.Code == 0 {
+		return fmt.Errorf("error %s: code is required", e.Name)
+	}
+	if e.HTTP != 0 && (e.HTTP < 400 || e.HTTP > 599 || http.StatusText(e.HTTP) == "") {
+		return fmt.Errorf("error %s: invalid HTTP status %d, use 400-599", e.Name, e.HTTP)
+	}
+	if e.GRPC != "" && !grpcCodes[e.GRPC] {
+		return fmt.Errorf("error %s: unknown gRPC code %q", e.Name, e.GRPC)
+	}
+	return nil
+}
+
+// parseDomainError разбирает строку блока: имя, сообщение в кавычках и опции code=, http=, grpc=, retryable
+func parseDomainError(text string) (DomainError, error) {
+	var e DomainError
+	name, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+	e.Name = name
+	rest = strings.TrimSpace(rest)
+
+	message, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return e, fmt.Errorf("error %s: message must be a quoted string", name)
+	}
+	e.Message, _ = strconv.Unquote(message)
+
+	for key, value := range parseOptions(rest[len(message):]).Options {
+		switch key {
+		case "code":
+			e.Code, err = strconv.Atoi(value)
+		case "http":
+			e.HTTP, err = strconv.Atoi(value)
+		case "grpc":
+			e.GRPC = value
+		case "retryable":
+			e.Retryable = true
+		default:
+			return e, fmt.Errorf("error %s: unknown option %q", name, key)
+		}
+		if err != nil {
+			return e, fmt.Errorf("error %s: invalid %s %q", name, key, value)
+		}
+	}
+	return e, e.check()
+}
+
+// domainErrors возвращает ошибки сервиса: настройки errors, затем блок //servicegen:errors исходного файла,
+// объявление в блоке заменяет одноимённое из настроек
+func (r ServiceGenerator) domainErrors() ([]DomainError, Diagnostics) {
+	var (
+		ret         []DomainError
+		diagnostics Diagnostics
+	)
+	index := map[string]int{}
+	add := func(e DomainError) {
+		if i, ok := index[e.Name]; ok {
+			ret[i] = e
+			return
+		}
+		index[e.Name] = len(ret)
+		ret = append(ret, e)
+	}
+
+	for _, e := range r.Config.Errors {
+		if err := e.check(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+			continue
+		}
+		add(e)
+	}
+
+	declared := map[string]bool{}
+	for _, comment := range r.errorsBlock() {
+		e, err := parseDomainError(strings.TrimPrefix(comment.Text, "//"))
+		if err == nil && declared[e.Name] {
+			err = fmt.Errorf("error %s is declared twice", e.Name)
+		}
+		if err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(comment.Pos()), "", err.Error()))
+			continue
+		}
+		declared[e.Name] = true
+		add(e)
+	}
+
+	//Проверке запросов нужна своя ошибка, если блок её не объявил
+	if _, ok := index[validationError.Name]; !ok && r.hasValidation() {
+		add(validationError)
+	}
+
+	//По коду клиент восстанавливает ошибку-сигнал, коды не должны повторяться
+	codes := map[int]string{}
+	for _, e := range ret {
+		if other, ok := codes[e.Code]; ok {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", fmt.Sprintf("error %s: code %d is already used by %s", e.Name, e.Code, other)))
+			continue
+		}
+		codes[e.Code] = e.Name
+	}
+	return ret, diagnostics
+}
+
+// errorsBlock возвращает строки блока //servicegen:errors без первой
+func (r ServiceGenerator) errorsBlock() []*ast.Comment {
+	for _, group := range r.Comments {
+		if len(group.List) == 0 {
+			continue
+		}
+		if _, ok := ParseDirective(group.List[0].Text, ErrorsMarker); !ok {
+			continue
+		}
+		var ret []*ast.Comment
+		for _, comment := range group.List[1:] {
+			if strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")) != "" {
+				ret = append(ret, comment)
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
+// Errors - ошибки-сигналы пакета сервиса
+func (p templateParams) Errors() []DomainError {
+	ret, _ := p.generator.domainErrors()
+	return ret
+}
+
+// CodeErrors - ошибки, которые клиент восстанавливает по коду: код не повторяется у других ошибок
+// и не совпадает с кодом 500 прочих ошибок
+func (p templateParams) CodeErrors() []DomainError {
+	errs := p.Errors()
+	count := map[int]int{}
+	for _, e := range errs {
+		count[e.Code]++
+	}
+	var ret []DomainError
+	for _, e := range errs {
+		if count[e.Code] == 1 && e.Code != http.StatusInternalServerError {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// RetryableError - первая ошибка, вызов с которой можно повторить, для тестов повторной доставки
+func (p templateParams) RetryableError() *DomainError {
+	for _, e := range p.Errors() {
+		if e.Retryable {
+			return &e
+		}
+	}
+	return nil
+}
+
+// GRPCErrors - ошибки, которые gRPC транспорт возвращает статусом
+func (p templateParams) GRPCErrors() []DomainError {
+	var ret []DomainError
+	for _, e := range p.Errors() {
+		if e.GRPC != "" {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
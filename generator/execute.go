@@ -7,23 +7,57 @@ import (
 )
 
 const (
-	ImplementationPackage = "implementation"
-	CmdPackage            = "cmd"
-	OtelTracingPackage    = "otelTracing"
-	ConfigPackage         = "config"
-	RootFilename          = "root"
-	HttpRunFilename       = "httprun"
-	TransportPackage      = "transport"
-	HttpPackage           = "httptransport"
-	NatsPackage           = "natstransport"
-	MiddlewarePackage     = "middleware"
-	HttpFileName          = "http"
-	NatsFileName          = "nats"
-	LoggingFileName       = "logging"
-	TracingFileName       = "tracing"
-	ErrorFileName         = "error"
+	ImplementationPackage  = "implementation"
+	CmdPackage             = "cmd"
+	OtelTracingPackage     = "otelTracing"
+	ConfigPackage          = "config"
+	RootFilename           = "root"
+	HttpRunFilename        = "httprun"
+	ClientCmdFilename      = "client"
+	ClientCmdTestFileName  = "client_gen_test.go"
+	TransportPackage       = "transport"
+	HttpPackage            = "httptransport"
+	NatsPackage            = "natstransport"
+	KafkaPackage           = "kafkatransport"
+	AmqpPackage            = "amqptransport"
+	JsonRpcPackage         = "jsonrpctransport"
+	MiddlewarePackage      = "middleware"
+	HttpFileName           = "http"
+	HttpClientFileName     = "client"
+	HttpTestFileName       = "http_gen_test.go"
+	HttpStreamTestFileName = "stream_gen_test.go"
+	NatsFileName           = "nats"
+	NatsClientFileName     = "client"
+	NatsClientTestFileName = "client_gen_test.go"
+	JetStreamFileName      = "jetstream"
+	JetStreamTestFileName  = "jetstream_gen_test.go"
+	KafkaFileName          = "kafka"
+	KafkaTestFileName      = "kafka_gen_test.go"
+	AmqpFileName           = "amqp"
+	AmqpClientFileName     = "client"
+	AmqpTestFileName       = "amqp_gen_test.go"
+	JsonRpcFileName        = "jsonrpc"
+	JsonRpcClientFileName  = "client"
+	JsonRpcTestFileName    = "jsonrpc_gen_test.go"
+	LoggingFileName        = "logging"
+	TracingFileName        = "tracing"
+	ErrorFileName          = "error"
+	GrpcPackage            = "grpctransport"
+	GrpcFileName           = "grpc"
+	GrpcRunFilename        = "grpcrun"
+	GrpcTestFileName       = "grpc_gen_test.go"
+	ProtoPackage           = "pb"
+	ProtoFileName          = "service.proto"
+	ServiceFileName        = "service"
+	OpenAPIYAMLFileName    = "openapi.yaml"
+	OpenAPIJSONFileName    = "openapi.json"
+	AsyncAPIYAMLFileName   = "asyncapi.yaml"
+	AsyncAPIJSONFileName   = "asyncapi.json"
 )
 
+// HTTPPathPrefix - группа маршрутов сервиса на HTTP сервере
+const HTTPPathPrefix = "/api/v1"
+
 type templateParams struct {
 	ServiceName      string
 	ServicePackage   string
@@ -31,84 +65,35 @@ type templateParams struct {
 	PackagePath      string
 	TransportPackage string
 	ModuleName       string
-}
+	Annotation       Annotation
+	HTTPPathPrefix   string
 
-func (r ServiceGenerator) ExecuteTemplate(buf *bytes.Buffer, packageName string, fileName string, params templateParams) error {
-	var err error
-
-	switch packageName {
-	case ImplementationPackage:
-		err = templates.ServiceTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case TransportPackage:
-		err = templates.TransportTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case HttpPackage:
-		err = templates.HttpTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case ConfigPackage:
-		err = templates.ConfigTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case OtelTracingPackage:
-		err = templates.TracingTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case NatsPackage:
-		err = templates.NatsTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case r.ServicePackageName:
-		err = templates.ErrorTemplate.Execute(buf, params)
-		if err != nil {
-			return fmt.Errorf("execute template: %v", err)
-		}
-	case MiddlewarePackage:
+	generator ServiceGenerator
+}
 
-		switch fileName {
-		case LoggingFileName:
-			err = templates.LoggingTemplate.Execute(buf, params)
-			if err != nil {
-				return fmt.Errorf("execute template: %v", err)
-			}
-		case TracingFileName:
-			err = templates.InstrumentationTemplate.Execute(buf, params)
-			if err != nil {
-				return fmt.Errorf("execute template: %v", err)
-			}
+// WithFunctions возвращает параметры шаблона с другим набором методов, для общих частей шаблонов:
+//
+//	{{ template "clientCalls" (.WithFunctions .NATSCore) }}
+func (p templateParams) WithFunctions(functions []ServiceFunction) templateParams {
+	p.Functions = functions
+	return p
+}
 
-		default:
-			return fmt.Errorf("execute template: unknown fileName")
-		}
-	case CmdPackage:
-		switch fileName {
-		case RootFilename:
-			err = templates.RootTemplate.Execute(buf, params)
-			if err != nil {
-				return fmt.Errorf("execute template: %v", err)
-			}
-		case HttpRunFilename:
-			err = templates.HttpRunTemplate.Execute(buf, params)
-			if err != nil {
-				return fmt.Errorf("execute template: %v", err)
-			}
+func (r ServiceGenerator) ExecuteTemplate(buf *bytes.Buffer, artifact Artifact, params templateParams) error {
+	loader := r.Templates
+	if loader == nil {
+		loader = templates.NewLoader("")
+	}
 
-		default:
-			return fmt.Errorf("execute template: unknown fileName")
-		}
+	tmpl, err := loader.Lookup(artifact.TemplateName())
+	if err != nil {
+		return fmt.Errorf("execute template: %v", err)
+	}
 
-	default:
-		return fmt.Errorf("execute template: unknown packageName")
+	err = tmpl.Execute(buf, params)
+	if err != nil {
+		return fmt.Errorf("execute template: %v", err)
 	}
 
-	return err
+	return nil
 }
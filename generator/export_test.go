@@ -0,0 +1,4 @@
+package generator
+
+// GoCamelCase - правило именования полей protoc-gen-go для заглушек protobuf в тестах
+var GoCamelCase = goCamelCase
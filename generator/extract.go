@@ -3,124 +3,460 @@ package generator
 import (
 	"errors"
 	"fmt"
+	"github.com/pablogolobaro/servicegen/templates"
 	"github.com/pablogolobaro/servicegen/utils"
 	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
 	"strings"
 )
 
 type ServiceFunction struct {
-	Name                string      //Имя функции
-	Signature           string      // Полная сигнатура
-	Arguments           []parameter // Список аргументов
-	ResultFullSignature string      // Полный набор возвращаемых значений в виде строки
-	Results             []parameter // Список возвращаемых значений
+	Name                string       //Имя функции
+	Doc                 string       // Комментарий метода в интерфейсе
+	Signature           string       // Полная сигнатура
+	Arguments           []parameter  // Список аргументов
+	Params              []parameter  // Аргументы без context.Context - поля запроса
+	ResultFullSignature string       // Тип единственного возвращаемого значения
+	Results             []parameter  // Список возвращаемых значений, последнее - error
+	Values              []parameter  // Возвращаемые значения без error - поля ответа
+	NATSSubject         string       // Тема NATS метода
+	NATSQueue           string       // Группа очереди подписчиков NATS, пустая - без группы
+	JetStream           bool         // Метод обрабатывается durable consumer JetStream
+	KafkaTopic          string       // Топик запросов Kafka
+	KafkaEvents         string       // Топик событий Kafka, пустой - без событий
+	Stream              bool         // Метод возвращает канал значений, транспорт передаёт их потоком
+	StreamType          string       // Тип элемента канала потокового метода
+	Validations         []validation // Проверки полей запроса из директив //servicegen:validate
 }
 
 type parameter struct {
-	Name string
-	Type string
+	Name      string // Имя переменной в сгенерированном коде
+	Type      string // Тип, квалифицированный пакетом сервиса
+	Field     string // Имя поля в структурах запроса и ответа
+	JSON      string // Имя JSON поля запроса или результата
+	OmitEmpty bool   // Пустое значение не передаётся в JSON
+}
+
+// findField возвращает поле списка по имени аргумента или результата, nil - если такого нет
+func findField(list []parameter, name string) *parameter {
+	field := templates.UpperFirstLetter(name)
+	for i := range list {
+		if list[i].Field == field {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// reserved - идентификаторы, которые шаблоны используют сами,
+// переменные с такими именами переименовываются
+var reserved = map[string]bool{
+	"s":        true,
+	"mw":       true,
+	"req":      true,
+	"request":  true,
+	"begin":    true,
+	"lvs":      true,
+	"err":      true,
+	"resp":     true,
+	"response": true,
+}
+
+// HTTPMethod - метод HTTP запроса к методу сервиса: POST с параметрами в теле запроса,
+// GET без тела для методов без параметров
+func (f ServiceFunction) HTTPMethod() string {
+	if len(f.Params) > 0 {
+		return http.MethodPost
+	}
+	return http.MethodGet
+}
+
+// HTTPPath - путь метода сервиса относительно HTTPPathPrefix
+func (f ServiceFunction) HTTPPath() string {
+	return "/" + strings.ToLower(f.Name)
+}
+
+// Context возвращает выражение контекста для вызова транспорта:
+// аргумент метода или context.Background(), если метод его не принимает
+func (f ServiceFunction) Context() string {
+	for _, argument := range f.Arguments {
+		if argument.Name == contextName {
+			return contextName
+		}
+	}
+	return "context.Background()"
 }
 
 func (r ServiceGenerator) convertFunctions() ([]ServiceFunction, error) {
 	ret := []ServiceFunction{}
+	//Собираем все ошибки интерфейса, а не только первую
+	var diagnostics Diagnostics
+	//Темы NATS разных методов не должны совпадать
+	subjects := map[string]string{}
+	//Как и темы NATS, топики запросов и событий разных методов не должны совпадать
+	topics := map[string]string{}
+	if r.Annotation.Has("kafka") {
+		if err := r.checkKafkaService(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		}
+	}
+	if httpServer(r) {
+		if err := r.checkHTTPRouter(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		}
+	}
+	if r.Annotation.Has("http") {
+		if statuses, err := r.httpStatuses(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		} else {
+			domainErrors, _ := r.domainErrors()
+			for _, err := range undeclaredErrors(domainErrors, statuses) {
+				diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+			}
+			for _, err := range unmappedErrors(domainErrors, statuses) {
+				diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+			}
+		}
+		if err := r.checkHTTPErrors(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		}
+		if err := r.checkHTTPResponse(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		}
+	}
+	namingErr := r.checkJSONNaming()
+	if namingErr != nil {
+		diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", namingErr.Error()))
+	}
+	_, errorDiagnostics := r.domainErrors()
+	diagnostics = append(diagnostics, errorDiagnostics...)
+	if r.Annotation.Has("jsonrpc") {
+		if err := r.checkJSONRPCPath(); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(r.TypeSpec.Pos()), "", err.Error()))
+		}
+	}
 	for _, method := range r.Methods {
-		arguments, err := extractArguments(method.Type)
-		if err != nil {
-			return ret, err
+		if len(method.Names) == 0 {
+			diagnostics = append(diagnostics, newDiagnostic(r.position(method.Pos()), "", "embedded interfaces are not supported"))
+			continue
+		}
+		name := method.Names[0].Name
+		pos := r.position(method.Pos())
+		if err := checkMethodDirectives(method); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+			continue
 		}
 
-		result, err := extractFullResultSignature(method.Type)
-		if err != nil {
-			return nil, err
+		arguments, argErr := r.extractArguments(method.Type)
+		if argErr != nil {
+			diagnostics = append(diagnostics, newDiagnostic(pos, name, argErr.Error()))
 		}
 
-		resultParameters, err := extractResults(method.Type)
-		if err != nil {
-			return nil, err
+		resultParameters, resErr := r.extractResults(method.Type, arguments)
+		if resErr != nil {
+			diagnostics = append(diagnostics, newDiagnostic(pos, name, resErr.Error()))
 		}
-		signSting, err := utils.Expr2string(method.Type)
-		if err != nil {
-			return nil, err
+		if argErr != nil || resErr != nil {
+			continue
 		}
-		//log.Fatalf("error print expression to string: #{err}")
+
 		f := ServiceFunction{
-			Name:                method.Names[0].Name,
-			Signature:           strings.TrimPrefix(signSting, "func"),
-			Arguments:           arguments,
-			ResultFullSignature: result,
-			Results:             resultParameters,
+			Name:      name,
+			Doc:       strings.TrimSpace(method.Doc.Text()),
+			Signature: signature(arguments, resultParameters),
+			Arguments: arguments,
+			Results:   resultParameters,
+			Values:    resultParameters[:len(resultParameters)-1],
+		}
+		for _, argument := range arguments {
+			if argument.Name != contextName {
+				f.Params = append(f.Params, argument)
+			}
+		}
+		if namingErr == nil {
+			if err := r.jsonFields(method, &f); err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+				continue
+			}
+		}
+		rules, err := validations(method, f.Params)
+		if err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+			continue
+		}
+		f.Validations = rules
+		if len(f.Values) == 1 {
+			f.ResultFullSignature = f.Values[0].Type
+			f.StreamType, f.Stream = streamElement(f.Values[0].Type)
+		}
+		if f.Stream {
+			if err := r.checkStreamTransports(); err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+				continue
+			}
+		}
+		if r.Annotation.Has("nats") {
+			binding, err := r.natsBinding(method, name)
+			if err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+				continue
+			}
+			if other, ok := subjects[binding.Subject]; ok {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, fmt.Sprintf("NATS subject %q is already used by method %s", binding.Subject, other)))
+				continue
+			}
+			//Обработчик JetStream не отвечает вызывающему, результаты некуда вернуть
+			if binding.JetStream && len(f.Values) > 0 {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, "JetStream methods must return only error"))
+				continue
+			}
+			subjects[binding.Subject] = name
+			f.NATSSubject, f.NATSQueue, f.JetStream = binding.Subject, binding.Queue, binding.JetStream
+		}
+		if r.Annotation.Has("kafka") {
+			topic, events, err := r.kafkaBinding(method, name)
+			if err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, err.Error()))
+				continue
+			}
+			if other, ok := topics[topic]; ok {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, fmt.Sprintf("Kafka topic %q is already used by method %s", topic, other)))
+				continue
+			}
+			if other, ok := topics[events]; ok && events != "" {
+				diagnostics = append(diagnostics, newDiagnostic(pos, name, fmt.Sprintf("Kafka topic %q is already used by method %s", events, other)))
+				continue
+			}
+			topics[topic] = name
+			if events != "" {
+				topics[events] = name
+			}
+			f.KafkaTopic, f.KafkaEvents = topic, events
 		}
 		ret = append(ret, f)
 	}
+	if len(diagnostics) > 0 {
+		return nil, diagnostics
+	}
 	return ret, nil
 }
 
-func extractArguments(spec ast.Expr) ([]parameter, error) {
+// position возвращает позицию узла исходного файла
+func (r ServiceGenerator) position(pos token.Pos) token.Position {
+	if r.Fset == nil {
+		return token.Position{}
+	}
+	return r.Fset.Position(pos)
+}
+
+// unsupported сообщает, содержит ли тип то, что нельзя передать по сети
+func unsupported(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.ChanType, *ast.FuncType:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+const (
+	contextName = "ctx"
+	errorName   = "err"
+)
+
+func (r ServiceGenerator) extractArguments(spec ast.Expr) ([]parameter, error) {
 	ret := []parameter{}
 	funcType, ok := spec.(*ast.FuncType)
 	if !ok {
 		return nil, errors.New("type is not *ast.FuncType")
 	}
 	for _, param := range funcType.Params.List {
-		switch paramType := param.Type.(type) {
-		case *ast.Ident:
-			for _, name := range param.Names {
-				ret = append(ret, parameter{Name: name.Name, Type: paramType.Name})
+		if _, ok := param.Type.(*ast.Ellipsis); ok {
+			return nil, errors.New("variadic arguments are not supported")
+		}
+
+		paramType, err := r.qualify(param.Type)
+		if err != nil {
+			return nil, err
+		}
+		if unsupported(param.Type) {
+			str, _ := utils.Expr2string(param.Type)
+			return nil, fmt.Errorf("unsupported argument type %s", str)
+		}
+
+		//Безымянные аргументы получают имена, контекст всегда называется ctx
+		names := param.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: "_"}}
+		}
+		for _, name := range names {
+			argName := name.Name
+			switch {
+			case paramType == "context.Context":
+				argName = contextName
+			case argName == "_":
+				argName = fmt.Sprintf("arg%d", len(ret))
 			}
-		case *ast.SelectorExpr:
-			x := paramType.X.(*ast.Ident)
-			sel := paramType.Sel
-			for _, name := range param.Names {
-				ret = append(ret, parameter{Name: name.Name, Type: fmt.Sprintf("%s.%s", x.Name, sel.Name)})
+			field := templates.UpperFirstLetter(argName)
+			for reserved[argName] {
+				argName += "_"
 			}
+			ret = append(ret, parameter{Name: argName, Type: paramType, Field: field})
 		}
-
 	}
 	return ret, nil
 }
 
-func extractFullResultSignature(spec ast.Expr) (string, error) {
-	ret := ""
+func (r ServiceGenerator) extractResults(spec ast.Expr, arguments []parameter) ([]parameter, error) {
+	ret := []parameter{}
 	funcType, ok := spec.(*ast.FuncType)
 	if !ok {
-		return "", errors.New("type is not *ast.FuncType")
+		return nil, errors.New("type is not *ast.FuncType")
 	}
-	resultType, ok := funcType.Results.List[0].Type.(*ast.Ident)
-	if !ok {
-		return "", errors.New("resultType is not *ast.Ident")
+	if funcType.Results == nil {
+		return nil, errors.New("last result must be error")
 	}
 
-	ret = resultType.Name
+	var names []string
+	for i, resultField := range funcType.Results.List {
+		resultType, err := r.qualify(resultField.Type)
+		if err != nil {
+			return nil, err
+		}
+		//Канал только для чтения допустим единственным значением потокового метода: (<-chan T, error)
+		if ch, ok := resultField.Type.(*ast.ChanType); ok && ch.Dir == ast.RECV && !unsupported(ch.Value) {
+			if i != 0 || len(resultField.Names) > 1 || len(funcType.Results.List) != 2 {
+				return nil, errors.New("streaming methods must return (<-chan T, error)")
+			}
+		} else if unsupported(resultField.Type) {
+			str, _ := utils.Expr2string(resultField.Type)
+			return nil, fmt.Errorf("unsupported result type %s", str)
+		}
 
-	return ret, nil
-}
+		if len(resultField.Names) == 0 {
+			ret = append(ret, parameter{Type: resultType})
+			names = append(names, "")
+			continue
+		}
+		for _, name := range resultField.Names {
+			ret = append(ret, parameter{Type: resultType})
+			names = append(names, name.Name)
+		}
+	}
 
-func extractResults(spec ast.Expr) ([]parameter, error) {
-	ret := []parameter{}
-	funcType, ok := spec.(*ast.FuncType)
-	if !ok {
-		return nil, errors.New("type is not *ast.FuncType")
+	if ret[len(ret)-1].Type != "error" {
+		return nil, errors.New("last result must be error")
 	}
 
-	for _, resultField := range funcType.Results.List {
+	//Имена значений нужны сгенерированному коду: именованные сохраняем,
+	//остальные называем res или res0, res1...
+	used := map[string]bool{}
+	for name := range reserved {
+		used[name] = true
+	}
+	for _, argument := range arguments {
+		used[argument.Name] = true
+	}
+	ret[len(ret)-1].Name = errorName
+	for i := range ret[:len(ret)-1] {
+		name := names[i]
+		if name == "" || name == "_" {
+			name = "res"
+			if len(ret) > 2 {
+				name = fmt.Sprintf("res%d", i)
+			}
+		}
+		ret[i].Field = templates.UpperFirstLetter(name)
+		for used[name] {
+			name += "_"
+		}
+		used[name] = true
+		ret[i].Name = name
+	}
 
-		switch resultType := resultField.Type.(type) {
+	return ret, nil
+}
 
-		case *ast.Ident:
-			param := parameter{Type: resultType.Name}
-			ret = append(ret, param)
+// signature собирает сигнатуру метода из разобранных аргументов и результатов
+func signature(arguments, results []parameter) string {
+	args := make([]string, 0, len(arguments))
+	for _, argument := range arguments {
+		args = append(args, argument.Name+" "+argument.Type)
+	}
+	resultTypes := make([]string, 0, len(results))
+	for _, result := range results {
+		resultTypes = append(resultTypes, result.Type)
+	}
+	if len(resultTypes) == 1 {
+		return fmt.Sprintf("(%s) %s", strings.Join(args, ", "), resultTypes[0])
+	}
+	return fmt.Sprintf("(%s) (%s)", strings.Join(args, ", "), strings.Join(resultTypes, ", "))
+}
 
-		case *ast.SelectorExpr:
-			x := resultType.X.(*ast.Ident)
-			sel := resultType.Sel
-			param := parameter{Type: fmt.Sprintf("%s.%s", x.Name, sel.Name)}
-			ret = append(ret, param)
+// qualify печатает тип так, чтобы его можно было использовать вне пакета сервиса:
+// типы, объявленные в пакете сервиса, получают префикс его имени
+func (r ServiceGenerator) qualify(expr ast.Expr) (string, error) {
+	str, err := utils.Expr2string(expr)
+	if err != nil {
+		return "", err
+	}
+	//Работаем с копией, исходное дерево не трогаем
+	clone, err := parser.ParseExpr(str)
+	if err != nil {
+		return "", fmt.Errorf("parse type %s: %v", str, err)
+	}
+	clone = qualifyExpr(clone, r.ServicePackageName)
+	return utils.Expr2string(clone)
+}
 
-		default:
-			return nil, fmt.Errorf("unknown type of Results.Field")
+func qualifyExpr(expr ast.Expr, pkg string) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, ok := types.Universe.Lookup(t.Name).(*types.TypeName); ok {
+			return t
 		}
-
+		return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: t}
+	case *ast.StarExpr:
+		t.X = qualifyExpr(t.X, pkg)
+	case *ast.ArrayType:
+		t.Elt = qualifyExpr(t.Elt, pkg)
+	case *ast.MapType:
+		t.Key = qualifyExpr(t.Key, pkg)
+		t.Value = qualifyExpr(t.Value, pkg)
+	case *ast.ChanType:
+		t.Value = qualifyExpr(t.Value, pkg)
+	case *ast.Ellipsis:
+		t.Elt = qualifyExpr(t.Elt, pkg)
+	case *ast.IndexExpr:
+		t.X = qualifyExpr(t.X, pkg)
+		t.Index = qualifyExpr(t.Index, pkg)
+	case *ast.IndexListExpr:
+		t.X = qualifyExpr(t.X, pkg)
+		for i := range t.Indices {
+			t.Indices[i] = qualifyExpr(t.Indices[i], pkg)
+		}
+	case *ast.FuncType:
+		qualifyFields(t.Params, pkg)
+		qualifyFields(t.Results, pkg)
+	case *ast.StructType:
+		qualifyFields(t.Fields, pkg)
+	case *ast.InterfaceType:
+		qualifyFields(t.Methods, pkg)
 	}
+	return expr
+}
 
-	return ret, nil
+func qualifyFields(fields *ast.FieldList, pkg string) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		field.Type = qualifyExpr(field.Type, pkg)
+	}
 }
@@ -2,84 +2,74 @@ package generator
 
 import (
 	"fmt"
-	"go/ast"
-	"go/printer"
-	"go/token"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
+	"sync"
 )
 
-func (r ServiceGenerator) GenerateFile(OutFile *ast.File, fileName string) error {
-	var packageName = OutFile.Name.Name
-	var filePath string
-	var dir string
-	var subDir string
+// FS - файловая система, в которую записываются результаты генерации
+type FS interface {
+	WriteFile(name string, data []byte) error
+}
 
-	switch packageName {
-	case r.ServicePackageName:
-		dir = ""
-	case CmdPackage:
-		dir = packageName
-	case OtelTracingPackage:
-		dir = packageName
-	case ConfigPackage:
-		dir = packageName
-	case ImplementationPackage:
-		dir = packageName
-	case TransportPackage:
-		dir = packageName
-	case MiddlewarePackage:
-		dir = packageName
-	case HttpPackage:
-		dir = filepath.Join(TransportPackage, packageName)
-		subDir = TransportPackage
-	case NatsPackage:
-		dir = filepath.Join(TransportPackage, packageName)
-		subDir = TransportPackage
-	}
+// DirFS пишет файлы на диск относительно каталога root, создавая недостающие каталоги
+type DirFS string
 
-	filePath = filepath.Join(dir, fileName) + "_gen.go"
+func (d DirFS) WriteFile(name string, data []byte) error {
+	path := filepath.Join(string(d), name)
 
-	if subDir != "" {
-		if err := createDir(subDir); err != nil {
-			return fmt.Errorf("create subDir: %v", err)
-		}
+	if err := createDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("create dir: %v", err)
 	}
 
-	if dir != "" {
-		if err := createDir(dir); err != nil {
-			return fmt.Errorf("create dir: %v", err)
-		}
+	//Не забываем обрезать старое содержимое файла
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write file: %v", err)
 	}
+	return nil
+}
 
-	//Подготовим файл конечного результата всей работы,
-	//назовем его созвучно файлу модели, добавим только суффикс _gen
-	outFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0755)
-	if err != nil {
-		return fmt.Errorf("create file: %v", err)
-	}
-	//Не забываем прибраться
-	defer outFile.Close()
-	//Печатаем результирующий AST в результирующий файл исходного кода
-	//«Печатаем» не следует понимать буквально,
-	//дерево разбора нельзя просто переписать в файл исходного кода,
-	//это совершенно разные форматы
-	//Мы здесь воспользуемся специализированным принтером из пакета ast/printer
-	err = printer.Fprint(outFile, token.NewFileSet(), OutFile)
-	if err != nil {
-		return fmt.Errorf("print file: %v", err)
-	}
+// MemFS хранит файлы в памяти
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func NewMemFS() *MemFS {
+	return &MemFS{files: map[string][]byte{}}
+}
+
+func (m *MemFS) WriteFile(name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	m.files[filepath.ToSlash(name)] = append([]byte(nil), data...)
 	return nil
 }
 
-func createDir(dir string) error {
-	err := os.Mkdir(dir, 0660)
-	if err != nil {
-		if !strings.Contains(err.Error(), "already exists.") {
-			return err
-		}
+// ReadFile возвращает содержимое записанного файла
+func (m *MemFS) ReadFile(name string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.files[filepath.ToSlash(name)]
+	return data, ok
+}
+
+// Names возвращает отсортированный список записанных файлов
+func (m *MemFS) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
 	}
-	return nil
+	sort.Strings(names)
+	return names
+}
+
+func createDir(dir string) error {
+	return os.MkdirAll(dir, 0755)
 }
@@ -3,28 +3,61 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"github.com/pablogolobaro/servicegen/templates"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
+	"path/filepath"
 )
 
 // ServiceGenerator - агрегатор данных для установки параметров в шаблоне
 type ServiceGenerator struct {
-	FileIdent          *ast.Ident           // Шапка файла
-	TypeSpec           *ast.TypeSpec        // Полная спецификация типа для интерфейса сервиса
-	Methods            []*ast.Field         // Набор методов интерфейса сервиса
-	OutFiles           map[string]*ast.File // Набор выходных файлов с подготовленной шапкой
-	PackagePath        string               // Относительный путь к исходному интерфейсу
-	ServicePackageName string               //пакэдж исходного файла
-	ModuleName         string               // имя модуля
+	FileIdent          *ast.Ident          // Шапка файла
+	TypeSpec           *ast.TypeSpec       // Полная спецификация типа для интерфейса сервиса
+	Methods            []*ast.Field        // Набор методов интерфейса сервиса
+	Annotation         Annotation          // Опции из комментария //servicegen:service
+	Config             Config              // Настройки генерации
+	PackagePath        string              // Относительный путь к исходному интерфейсу
+	ServicePackageName string              //пакэдж исходного файла
+	ModuleName         string              // имя модуля
+	Imports            []*ast.ImportSpec   // Импорты исходного файла
+	Comments           []*ast.CommentGroup // Комментарии исходного файла, в них ищется блок //servicegen:errors
+	Fset               *token.FileSet      // Позиции исходного файла для диагностики
+	Source             string              // Путь исходного файла
+	Templates          *templates.Loader   // Цепочка поиска шаблонов, по умолчанию без каталога проекта
 }
 
-func (r ServiceGenerator) Generate(outFile *ast.File, fileName string) error {
+// Check разбирает методы интерфейса и возвращает все найденные ошибки как Diagnostics
+func (r ServiceGenerator) Check() error {
+	_, err := r.convertFunctions()
+	return err
+}
+
+// OutputPath возвращает путь файла артефакта рядом с исходным файлом
+func (r ServiceGenerator) OutputPath(artifact Artifact) string {
+	return filepath.Join(filepath.Dir(r.Source), artifact.OutputPath(r))
+}
+
+// EnabledArtifacts возвращает артефакты реестра, включённые для сервиса
+func (r ServiceGenerator) EnabledArtifacts() []Artifact {
+	var ret []Artifact
+	for _, artifact := range Artifacts() {
+		if artifact.Enabled(r) {
+			ret = append(ret, artifact)
+		}
+	}
+	return ret
+}
+
+// Generate выполняет шаблон артефакта и возвращает отформатированный исходный код,
+// содержимое не Go артефактов возвращается без изменений
+func (r ServiceGenerator) Generate(artifact Artifact) ([]byte, error) {
 
 	//Аллокация и установка параметров для template
 	serviceFunctions, err := r.convertFunctions()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	params := templateParams{
 		//Параметры извлекаем из ресивера метода
@@ -34,41 +67,54 @@ func (r ServiceGenerator) Generate(outFile *ast.File, fileName string) error {
 		PackagePath:      r.PackagePath,
 		TransportPackage: TransportPackage,
 		ModuleName:       r.ModuleName,
+		Annotation:       r.Annotation,
+		HTTPPathPrefix:   HTTPPathPrefix,
+		generator:        r,
 	}
 
-	packageName := outFile.Name.Name
 	//Аллокация буфера,
 	//куда будем заливать выполненный шаблон
 	var buf bytes.Buffer
 	//Процессинг шаблона с подготовленными параметрами
 	//в подготовленный буфер
-	err = r.ExecuteTemplate(&buf, packageName, fileName, params)
+	err = r.ExecuteTemplate(&buf, artifact, params)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	//Не Go файлы (.proto, спецификации) отдаём как есть
+	if filepath.Ext(artifact.OutputPath(r)) != ".go" {
+		return buf.Bytes(), nil
 	}
 
 	//Теперь сделаем парсинг обработанного шаблона,
 	//который уже стал валидным кодом Go,
 	//в дерево разбора,
 	//получаем AST этого кода
+	fset := token.NewFileSet()
 	templateAst, err := parser.ParseFile(
-		token.NewFileSet(),
+		fset,
 		//Источник для парсинга лежит не в файле,
-		"",
+		r.OutputPath(artifact),
 		//а в буфере
 		buf.Bytes(),
 		//mode парсинга, нас интересуют в основном комментарии
 		parser.ParseComments,
 	)
 	if err != nil {
-		return fmt.Errorf("parse template: %v", err)
+		return nil, fmt.Errorf("parse template: %w", err)
 	}
 
-	//Добавляем декларации из полученного дерева
-	//в результирующий outFile *ast.File,
-	//переданный нам аргументом
-	for _, decl := range templateAst.Decls {
-		outFile.Decls = append(outFile.Decls, decl)
+	//Пакет выходного файла определяет артефакт, а не шаблон
+	templateAst.Name.Name = artifact.PackageName(r)
+
+	//Импорты типов из сигнатур методов добавляем, неиспользуемые убираем
+	r.fixImports(fset, templateAst)
+
+	//Печатаем дерево разбора обратно в исходный код вместе с комментариями
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, templateAst); err != nil {
+		return nil, fmt.Errorf("print file: %v", err)
 	}
-	return nil
+	return out.Bytes(), nil
 }
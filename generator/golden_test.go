@@ -0,0 +1,166 @@
+package generator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/pablogolobaro/servicegen/generator"
+	"github.com/pablogolobaro/servicegen/templates"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// corpus - исходные интерфейсы, по которым сверяются golden файлы, calc повторяет services/calc (TestCorpusCalc)
+var corpus = map[string]string{
+	"calc":    "testdata/corpus/calc/service.go",
+	"catalog": "testdata/corpus/catalog/service.go",
+	"stats":   "testdata/corpus/stats/service.go",
+	"clock":   "testdata/corpus/clock/service.go",
+	"feed":    "testdata/corpus/feed/service.go",
+}
+
+func TestGolden(t *testing.T) {
+	var generated []generator.File
+
+	for _, name := range corpusNames() {
+		source := corpus[name]
+
+		t.Run(name, func(t *testing.T) {
+			res, err := generator.Run(context.Background(), generator.Options{
+				Sources: []string{source},
+				//Пользовательские шаблоны не должны влиять на тест
+				Templates: templates.NewLoaderFS(templates.Defaults()),
+			})
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			generated = append(generated, res.Files...)
+
+			goldenDir := filepath.Join("testdata", "golden", name)
+			seen := map[string]bool{}
+
+			for _, file := range res.Files {
+				rel, err := filepath.Rel(filepath.Dir(source), file.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				goldenPath := filepath.Join(goldenDir, rel+".golden")
+				seen[goldenPath] = true
+
+				switch filepath.Ext(file.Path) {
+				case ".go":
+					if _, err := parser.ParseFile(token.NewFileSet(), file.Path, file.Content, parser.AllErrors); err != nil {
+						t.Errorf("%s does not parse: %v", rel, err)
+					}
+				case ".json":
+					if !json.Valid(file.Content) {
+						t.Errorf("%s is not valid JSON", rel)
+					}
+				case ".yaml":
+					var doc interface{}
+					if err := yaml.Unmarshal(file.Content, &doc); err != nil {
+						t.Errorf("%s does not parse: %v", rel, err)
+					}
+				}
+
+				if *update {
+					if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(goldenPath, file.Content, 0644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				want, err := os.ReadFile(goldenPath)
+				if err != nil {
+					t.Errorf("%s: %v (run go test ./generator -update)", rel, err)
+					continue
+				}
+				if !bytes.Equal(want, file.Content) {
+					t.Errorf("%s differs from %s (run go test ./generator -update)\n%s", rel, goldenPath, diffLines(want, file.Content))
+				}
+			}
+
+			//Golden файлы артефактов, которые больше не генерируются
+			filepath.WalkDir(goldenDir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || seen[path] {
+					return err
+				}
+				if *update {
+					return os.Remove(path)
+				}
+				t.Errorf("stale golden file %s", path)
+				return nil
+			})
+		})
+	}
+
+	t.Run("typecheck", func(t *testing.T) {
+		//protoc в тестах не запускаем, пакеты pb подменяем заглушками
+		stubs, err := protoStubs(generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := generator.Verify(context.Background(), append(generated, stubs...)); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// TestCorpusCalc сверяет calc корпуса с services/calc: копия нужна модулю testdata, в котором собираются
+// сгенерированные тесты, но golden файлы должны отражать пример из репозитория
+func TestCorpusCalc(t *testing.T) {
+	want, err := os.ReadFile("../services/calc/service.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(corpus["calc"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("%s differs from services/calc/service.go, copy it and run go test -update:\n%s", corpus["calc"], diffLines(want, got))
+	}
+}
+
+func corpusNames() []string {
+	var names []string
+	for name := range corpus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffLines показывает первую отличающуюся строку
+func diffLines(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return "line " + strconv.Itoa(i+1) + ":\n- " + w + "\n+ " + g
+		}
+	}
+	return ""
+}
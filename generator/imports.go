@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"golang.org/x/tools/go/ast/astutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fixImports добавляет в файл импорты исходного файла, нужные типам из сигнатур методов,
+// и убирает импорты, которые не используются в сгенерированном коде
+func (r ServiceGenerator) fixImports(fset *token.FileSet, file *ast.File) {
+	for _, spec := range r.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			astutil.AddNamedImport(fset, file, spec.Name.Name, importPath)
+		} else {
+			astutil.AddImport(fset, file, importPath)
+		}
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	for _, spec := range append([]*ast.ImportSpec(nil), file.Imports...) {
+		name, ok := r.importName(spec)
+		if !ok || used[name] {
+			continue
+		}
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			astutil.DeleteNamedImport(fset, file, spec.Name.Name, importPath)
+		} else {
+			astutil.DeleteImport(fset, file, importPath)
+		}
+	}
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName возвращает имя, под которым импортирован пакет.
+// ok=false, если имя нельзя надёжно определить по пути импорта
+func (r ServiceGenerator) importName(spec *ast.ImportSpec) (string, bool) {
+	if spec.Name != nil {
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return "", false
+		}
+		return spec.Name.Name, true
+	}
+
+	importPath, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return "", false
+	}
+	if importPath == r.PackagePath {
+		return r.ServicePackageName, true
+	}
+
+	name := path.Base(importPath)
+	if majorVersion.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	if !token.IsIdentifier(name) || strings.Contains(name, "-") {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,107 @@
+package generator_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pablogolobaro/servicegen/generator"
+	"github.com/pablogolobaro/servicegen/templates"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// module - отдельный модуль testdata, в котором лежит корпус и зависимости сгенерированного кода
+const module = "github.com/pablogolobaro/servicegen/generator/testdata"
+
+// TestGeneratedTests запускает тесты, которые генератор кладёт в проект сервиса.
+// Сгенерированные файлы подкладываются через go test -overlay, дерево исходников не меняется
+func TestGeneratedTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test on generated packages")
+	}
+
+	root, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replace := map[string]string{}
+	packages := map[string]bool{}
+	dir := t.TempDir()
+
+	for _, name := range corpusNames() {
+		res, err := generator.Run(context.Background(), generator.Options{
+			Sources:   []string{corpus[name]},
+			Templates: templates.NewLoaderFS(templates.Defaults()),
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		//Пакет pb собирается из заглушек вместо результата protoc
+		stubs, err := protoStubs(res.Files)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for i, file := range append(res.Files, stubs...) {
+			abs, err := filepath.Abs(file.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := filepath.Join(dir, fmt.Sprintf("%s_%d_%s", name, i, filepath.Base(file.Path)))
+			if err := os.WriteFile(content, file.Content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			replace[abs] = content
+
+			if strings.HasSuffix(file.Path, "_test.go") {
+				rel, err := filepath.Rel(root, filepath.Dir(abs))
+				if err != nil {
+					t.Fatal(err)
+				}
+				packages[path.Join(module, filepath.ToSlash(rel))] = true
+			}
+		}
+	}
+	if len(packages) == 0 {
+		t.Skip("no generated tests")
+	}
+
+	overlay, err := json.Marshal(struct{ Replace map[string]string }{replace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlayPath := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlayPath, overlay, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var list []string
+	for pkg := range packages {
+		list = append(list, pkg)
+	}
+	sort.Strings(list)
+
+	//Тестовый бинарник запускается в каталоге пакета, которого нет на диске,
+	//поэтому собираем его отдельно и запускаем во временном каталоге
+	for i, pkg := range list {
+		binary := filepath.Join(dir, fmt.Sprintf("%d.test", i))
+		build := exec.Command("go", "test", "-c", "-vet=off", "-overlay", overlayPath, "-o", binary, pkg)
+		build.Dir = root
+		if out, err := build.CombinedOutput(); err != nil {
+			t.Fatalf("build %s: %v\n%s", pkg, err, out)
+		}
+
+		run := exec.Command(binary)
+		run.Dir = dir
+		out, err := run.CombinedOutput()
+		if err != nil {
+			t.Errorf("%s: %v\n%s", pkg, err, out)
+		}
+	}
+}
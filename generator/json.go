@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// JSONMarker - директива метода с тегами json полей запроса и результата:
+//
+//	//servicegen:json userID=user_id,omitempty total=,omitempty
+//
+// Имя, которое носят и аргумент, и результат, уточняется стороной: request.name, response.name.
+const JSONMarker = "servicegen:json"
+
+// Стороны полей в ключах директивы //servicegen:json
+const (
+	jsonSideRequest  = "request"  // Поле запроса - аргумент метода
+	jsonSideResponse = "response" // Поле результата
+)
+
+// Именование JSON полей запросов и результатов
+const (
+	jsonNamingLower = "lower" // UserID - userid, по умолчанию
+	jsonNamingCamel = "camel" // UserID - userId
+	jsonNamingSnake = "snake" // UserID - user_id
+	jsonNamingAsIs  = "as-is" // UserID - UserID, как encoding/json без тега
+)
+
+// Формы успешных ответов HTTP транспорта
+const (
+	httpResponseEnvelope = "envelope" // {success, result, error}, по умолчанию
+	httpResponseBare     = "bare"     // Только результат, ошибка - по статусу ответа
+)
+
+// jsonNaming возвращает именование полей: опция аннотации json.naming, затем настройки, затем умолчание
+func (r ServiceGenerator) jsonNaming() string {
+	if v := r.Annotation.Value("json.naming"); v != "" {
+		return v
+	}
+	if r.Config.JSON.Naming != "" {
+		return r.Config.JSON.Naming
+	}
+	return jsonNamingLower
+}
+
+// checkJSONNaming проверяет именование полей
+func (r ServiceGenerator) checkJSONNaming() error {
+	switch naming := r.jsonNaming(); naming {
+	case jsonNamingLower, jsonNamingCamel, jsonNamingSnake, jsonNamingAsIs:
+		return nil
+	default:
+		return fmt.Errorf("unknown JSON naming %q, use %s, %s, %s or %s", naming, jsonNamingLower, jsonNamingCamel, jsonNamingSnake, jsonNamingAsIs)
+	}
+}
+
+// jsonName - имя JSON поля по имени поля структуры
+func (r ServiceGenerator) jsonName(field string) string {
+	switch r.jsonNaming() {
+	case jsonNamingCamel:
+		return camelCase(field)
+	case jsonNamingSnake:
+		return snakeCase(field)
+	case jsonNamingAsIs:
+		return field
+	default:
+		return strings.ToLower(field)
+	}
+}
+
+// jsonFields назначает JSON имена полям запроса и результата метода: по именованию,
+// с omitempty из json.omitempty, затем по директиве //servicegen:json метода
+func (r ServiceGenerator) jsonFields(method *ast.Field, f *ServiceFunction) error {
+	omitEmpty := r.Annotation.Has("json.omitempty") || r.Config.JSON.OmitEmpty
+	for _, list := range [][]parameter{f.Params, f.Values} {
+		for i := range list {
+			list[i].JSON, list[i].OmitEmpty = r.jsonName(list[i].Field), omitEmpty
+		}
+	}
+
+	if directive, ok := methodDirective(method, JSONMarker); ok {
+		for key, tag := range directive.Options {
+			p, err := jsonField(f, key)
+			if err != nil {
+				return err
+			}
+			//Единственный результат передаётся без обёртки в поле result, тег ему не применяется
+			if len(f.Values) == 1 && p == &f.Values[0] {
+				return fmt.Errorf("json: result %s is the only result and is sent as result, it has no tag", key)
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				return fmt.Errorf("json: field %s cannot be skipped", key)
+			}
+			if name != "" {
+				p.JSON = name
+			}
+			for _, option := range strings.Split(options, ",") {
+				switch option {
+				case "":
+				case "omitempty":
+					p.OmitEmpty = true
+				default:
+					return fmt.Errorf("json: unknown option %q of %s", option, key)
+				}
+			}
+		}
+	}
+
+	//Поля одной структуры не должны совпадать по имени
+	for _, list := range [][]parameter{f.Params, f.Values} {
+		names := map[string]string{}
+		for _, p := range list {
+			if other, ok := names[p.JSON]; ok {
+				return fmt.Errorf("json: name %q of %s is already used by %s", p.JSON, p.Field, other)
+			}
+			names[p.JSON] = p.Field
+		}
+	}
+	return nil
+}
+
+// jsonField находит поле по ключу директивы //servicegen:json: имени аргумента или результата,
+// request.name или response.name
+func jsonField(f *ServiceFunction, key string) (*parameter, error) {
+	side, name, ok := strings.Cut(key, ".")
+	if !ok {
+		request, response := findField(f.Params, key), findField(f.Values, key)
+		switch {
+		case request != nil && response != nil:
+			return nil, fmt.Errorf("json: %s is both an argument and a result, use %s.%s or %s.%s", key, jsonSideRequest, key, jsonSideResponse, key)
+		case request != nil:
+			return request, nil
+		case response != nil:
+			return response, nil
+		}
+		return nil, fmt.Errorf("json: unknown argument or result %q", key)
+	}
+
+	var p *parameter
+	switch side {
+	case jsonSideRequest:
+		p = findField(f.Params, name)
+	case jsonSideResponse:
+		p = findField(f.Values, name)
+	default:
+		return nil, fmt.Errorf("json: unknown side %q of %s, use %s or %s", side, key, jsonSideRequest, jsonSideResponse)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("json: unknown %s field %q", side, name)
+	}
+	return p, nil
+}
+
+// Tag - значение тега json поля запроса или результата
+func (p parameter) Tag() string {
+	if p.OmitEmpty {
+		return p.JSON + ",omitempty"
+	}
+	return p.JSON
+}
+
+// httpResponse возвращает форму успешных ответов: опция аннотации http.response, затем настройки, затем умолчание
+func (r ServiceGenerator) httpResponse() string {
+	if v := r.Annotation.Value("http.response"); v != "" {
+		return v
+	}
+	if r.Config.HTTP.Response != "" {
+		return r.Config.HTTP.Response
+	}
+	return httpResponseEnvelope
+}
+
+// checkHTTPResponse проверяет форму успешных ответов
+func (r ServiceGenerator) checkHTTPResponse() error {
+	if shape := r.httpResponse(); shape != httpResponseEnvelope && shape != httpResponseBare {
+		return fmt.Errorf("unknown HTTP response shape %q, use %s or %s", shape, httpResponseEnvelope, httpResponseBare)
+	}
+	return nil
+}
+
+// Bare - HTTP транспорт отвечает на успешный вызов только результатом
+func (p templateParams) Bare() bool {
+	return p.generator.httpResponse() == httpResponseBare
+}
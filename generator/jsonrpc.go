@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultJSONRPCPath - путь JSON-RPC на HTTP сервере по умолчанию
+const defaultJSONRPCPath = "/rpc"
+
+// jsonRPCPath возвращает путь JSON-RPC: опция аннотации jsonrpc.path, затем настройки, затем умолчание
+func (r ServiceGenerator) jsonRPCPath() string {
+	if v := r.Annotation.Value("jsonrpc.path"); v != "" {
+		return v
+	}
+	if r.Config.JSONRPC.Path != "" {
+		return r.Config.JSONRPC.Path
+	}
+	return defaultJSONRPCPath
+}
+
+// checkJSONRPCPath проверяет, что путь можно зарегистрировать на echo сервере
+func (r ServiceGenerator) checkJSONRPCPath() error {
+	path := r.jsonRPCPath()
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t?#:*") {
+		return fmt.Errorf("invalid JSON-RPC path %q", path)
+	}
+	if path == "/metrics" {
+		return fmt.Errorf("JSON-RPC path %q overlaps the metrics", path)
+	}
+	if path == HTTPPathPrefix || strings.HasPrefix(path, HTTPPathPrefix+"/") {
+		return fmt.Errorf("JSON-RPC path %q overlaps the HTTP routes under %s", path, HTTPPathPrefix)
+	}
+	return nil
+}
+
+// JSONRPCPath - путь, на котором httprun монтирует JSON-RPC
+func (p templateParams) JSONRPCPath() string {
+	return p.generator.jsonRPCPath()
+}
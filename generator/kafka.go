@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultKafkaTopic   = "{service}.{version}.{method}"
+	defaultKafkaEvents  = "{service}.{version}.{method}.events"
+	defaultKafkaVersion = "v1"
+	defaultKafkaCommit  = "after"
+	// noKafkaEvents отключает события метода
+	noKafkaEvents = "-"
+	// maxKafkaTopic - предельная длина имени топика в Kafka
+	maxKafkaTopic = 249
+)
+
+// kafkaCommits - стратегии фиксации смещений и константы, которыми они представлены в сгенерированном коде
+var kafkaCommits = map[string]string{
+	"after":  "CommitAfter",
+	"before": "CommitBefore",
+	"sync":   "CommitSync",
+}
+
+var kafkaTopicRe = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// kafkaNaming собирает настройки Kafka сервиса: настройки, затем опции аннотации, затем умолчания
+func (r ServiceGenerator) kafkaNaming() KafkaConfig {
+	cfg := r.Config.Kafka
+	if v := r.Annotation.Value("kafka.topic"); v != "" {
+		cfg.Topic = v
+	}
+	if v := r.Annotation.Value("kafka.events"); v != "" {
+		cfg.Events = v
+	}
+	if v := r.Annotation.Value("kafka.version"); v != "" {
+		cfg.Version = v
+	}
+	if v := r.Annotation.Value("kafka.group"); v != "" {
+		cfg.Group = v
+	}
+	if v := r.Annotation.Value("kafka.commit"); v != "" {
+		cfg.Commit = v
+	}
+
+	if cfg.Topic == "" {
+		cfg.Topic = defaultKafkaTopic
+	}
+	if cfg.Events == "" {
+		cfg.Events = defaultKafkaEvents
+	}
+	if cfg.Version == "" {
+		cfg.Version = defaultKafkaVersion
+	}
+	if cfg.Group == "" {
+		cfg.Group = strings.ToLower(r.TypeSpec.Name.Name)
+	}
+	if cfg.Commit == "" {
+		cfg.Commit = defaultKafkaCommit
+	}
+	return cfg
+}
+
+// checkKafkaService проверяет настройки, общие для всех методов
+func (r ServiceGenerator) checkKafkaService() error {
+	cfg := r.kafkaNaming()
+	if _, ok := kafkaCommits[cfg.Commit]; !ok {
+		return fmt.Errorf("unknown Kafka commit strategy %q, use after, before or sync", cfg.Commit)
+	}
+	if strings.ContainsAny(cfg.Group, " \t") {
+		return fmt.Errorf("invalid Kafka consumer group %q", cfg.Group)
+	}
+	return nil
+}
+
+// kafkaBinding возвращает топики запросов и событий метода с учётом директивы //servicegen:kafka.
+// Пустой топик событий - метод их не публикует
+func (r ServiceGenerator) kafkaBinding(method *ast.Field, name string) (topic string, events string, err error) {
+	cfg := r.kafkaNaming()
+	if directive, ok := methodDirective(method, KafkaMarker); ok {
+		if v := directive.Value("topic"); v != "" {
+			cfg.Topic = v
+		}
+		if v := directive.Value("events"); v != "" {
+			cfg.Events = v
+		}
+	}
+
+	replacer := strings.NewReplacer(
+		"{service}", strings.ToLower(r.TypeSpec.Name.Name),
+		"{version}", cfg.Version,
+		"{method}", strings.ToLower(name),
+	)
+	topic = replacer.Replace(cfg.Topic)
+	if err := checkKafkaTopic(topic); err != nil {
+		return "", "", err
+	}
+	if cfg.Events == noKafkaEvents {
+		return topic, "", nil
+	}
+	events = replacer.Replace(cfg.Events)
+	if err := checkKafkaTopic(events); err != nil {
+		return "", "", err
+	}
+	return topic, events, nil
+}
+
+// checkKafkaTopic проверяет имя топика по правилам брокера
+func checkKafkaTopic(topic string) error {
+	if strings.ContainsAny(topic, "{}") {
+		return fmt.Errorf("invalid Kafka topic %q: unknown placeholder, use {service}, {version} or {method}", topic)
+	}
+	if !kafkaTopicRe.MatchString(topic) || len(topic) > maxKafkaTopic || topic == "." || topic == ".." {
+		return fmt.Errorf("invalid Kafka topic %q", topic)
+	}
+	return nil
+}
+
+// KafkaGroup - группа потребителей реплик сервиса
+func (p templateParams) KafkaGroup() string {
+	return p.generator.kafkaNaming().Group
+}
+
+// KafkaCommit - константа стратегии фиксации смещений по умолчанию
+func (p templateParams) KafkaCommit() string {
+	return kafkaCommits[p.generator.kafkaNaming().Commit]
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"github.com/pablogolobaro/servicegen/templates"
+	"strings"
+	"unicode"
+)
+
+// words делит имя на слова по заглавным буквам и подчёркиваниям, аббревиатура - одно слово: UserID - user, id
+func words(name string) []string {
+	runes := []rune(name)
+	var (
+		ret  []string
+		word []rune
+	)
+	for i, r := range runes {
+		if r == '_' {
+			if len(word) > 0 {
+				ret = append(ret, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			ret = append(ret, string(word))
+			word = nil
+		}
+		word = append(word, unicode.ToLower(r))
+	}
+	if len(word) > 0 {
+		ret = append(ret, string(word))
+	}
+	return ret
+}
+
+// snakeCase переводит UserID в user_id
+func snakeCase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// camelCase переводит UserID в userId
+func camelCase(s string) string {
+	ret := words(s)
+	for i := 1; i < len(ret); i++ {
+		ret[i] = templates.UpperFirstLetter(ret[i])
+	}
+	return strings.Join(ret, "")
+}
+
+// goCamelCase повторяет правило именования полей protoc-gen-go: user_id -> UserId
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			//Подчёркивание перед строчной буквой пропускается
+		case '0' <= c && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+const (
+	defaultNATSSubject = "{service}.{version}.{method}"
+	defaultNATSVersion = "v1"
+	// noNATSQueue отключает группу очереди: сообщение получает каждая реплика
+	noNATSQueue = "-"
+)
+
+// natsNaming собирает именование тем сервиса: настройки, затем опции аннотации, затем умолчания
+func (r ServiceGenerator) natsNaming() NATSConfig {
+	cfg := r.Config.NATS
+	if v := r.Annotation.Value("nats.subject"); v != "" {
+		cfg.Subject = v
+	}
+	if v := r.Annotation.Value("nats.version"); v != "" {
+		cfg.Version = v
+	}
+	if v := r.Annotation.Value("nats.queue"); v != "" {
+		cfg.Queue = v
+	}
+
+	if cfg.Subject == "" {
+		cfg.Subject = defaultNATSSubject
+	}
+	if cfg.Version == "" {
+		cfg.Version = defaultNATSVersion
+	}
+	if cfg.Queue == "" {
+		cfg.Queue = strings.ToLower(r.TypeSpec.Name.Name)
+	}
+	return cfg
+}
+
+// natsBinding - тема, группа очереди и режим доставки метода
+type natsBinding struct {
+	Subject   string
+	Queue     string
+	JetStream bool // Метод обрабатывается durable consumer JetStream, а не request-reply
+}
+
+// natsBinding возвращает настройки NATS метода с учётом директивы //servicegen:nats
+func (r ServiceGenerator) natsBinding(method *ast.Field, name string) (natsBinding, error) {
+	cfg := r.natsNaming()
+	jetStream := r.Annotation.Has("jetstream")
+	if directive, ok := methodDirective(method, NATSMarker); ok {
+		if v := directive.Value("subject"); v != "" {
+			cfg.Subject = v
+		}
+		if v := directive.Value("queue"); v != "" {
+			cfg.Queue = v
+		}
+		jetStream = jetStream || directive.Has("jetstream")
+	}
+
+	subject := strings.NewReplacer(
+		"{service}", strings.ToLower(r.TypeSpec.Name.Name),
+		"{version}", cfg.Version,
+		"{method}", strings.ToLower(name),
+	).Replace(cfg.Subject)
+	if err := checkNATSSubject(subject); err != nil {
+		return natsBinding{}, err
+	}
+
+	queue := cfg.Queue
+	if queue == noNATSQueue {
+		queue = ""
+	}
+	if strings.ContainsAny(queue, " \t") {
+		return natsBinding{}, fmt.Errorf("invalid NATS queue group %q", queue)
+	}
+	return natsBinding{Subject: subject, Queue: queue, JetStream: jetStream}, nil
+}
+
+// usesJetStream сообщает, есть ли у сервиса методы в режиме JetStream
+func (r ServiceGenerator) usesJetStream() bool {
+	if !r.Annotation.Has("nats") {
+		return false
+	}
+	if r.Annotation.Has("jetstream") {
+		return true
+	}
+	for _, method := range r.Methods {
+		if directive, ok := methodDirective(method, NATSMarker); ok && directive.Has("jetstream") {
+			return true
+		}
+	}
+	return false
+}
+
+// jetStreamName - имя потока JetStream сервиса
+func (r ServiceGenerator) jetStreamName() string {
+	return strings.ToUpper(r.TypeSpec.Name.Name)
+}
+
+// NATSCore возвращает методы, обслуживаемые подписчиками request-reply
+func (p templateParams) NATSCore() []ServiceFunction {
+	var ret []ServiceFunction
+	for _, function := range p.Functions {
+		if !function.JetStream {
+			ret = append(ret, function)
+		}
+	}
+	return ret
+}
+
+// NATSJetStream возвращает методы, обслуживаемые durable consumer JetStream
+func (p templateParams) NATSJetStream() []ServiceFunction {
+	var ret []ServiceFunction
+	for _, function := range p.Functions {
+		if function.JetStream {
+			ret = append(ret, function)
+		}
+	}
+	return ret
+}
+
+// JetStreamName - имя потока JetStream сервиса
+func (p templateParams) JetStreamName() string {
+	return p.generator.jetStreamName()
+}
+
+// JetStreamDurable - имя durable consumer метода
+func (p templateParams) JetStreamDurable(method string) string {
+	return strings.ToLower(p.ServiceName + "-" + method)
+}
+
+// checkNATSSubject проверяет, что тема пригодна для запроса: без пустых токенов и масок
+func checkNATSSubject(subject string) error {
+	if strings.ContainsAny(subject, "{}") {
+		return fmt.Errorf("invalid NATS subject %q: unknown placeholder, use {service}, {version} or {method}", subject)
+	}
+	for _, token := range strings.Split(subject, ".") {
+		if token == "" || token == "*" || token == ">" || strings.ContainsAny(token, " \t") {
+			return fmt.Errorf("invalid NATS subject %q", subject)
+		}
+	}
+	return nil
+}
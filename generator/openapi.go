@@ -0,0 +1,288 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// openAPIDocument - спецификация OpenAPI 3.0 HTTP транспорта
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi" yaml:"openapi"`
+	Info       openAPIInfo                             `json:"info" yaml:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths" yaml:"paths"`
+	Components openAPIComponents                       `json:"components" yaml:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId" yaml:"operationId"`
+	Summary     string                      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                      `json:"description,omitempty" yaml:"description,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses" yaml:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required" yaml:"required"`
+	Content  map[string]openAPIMediaType `json:"content" yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description" yaml:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas" yaml:"schemas"`
+}
+
+const (
+	openAPIVersion  = "3.0.3"
+	schemaRefPrefix = "#/components/schemas/"
+	jsonContentType = "application/json"
+	// problemContentType - ответ с ошибкой HTTP транспорта в формате problem
+	problemContentType = "application/problem+json"
+	// problemSchema - схема ответа с ошибкой по RFC 7807
+	problemSchema = "Problem"
+	// eventStreamContentType - ответ потоковых методов HTTP транспорта
+	eventStreamContentType = "text/event-stream"
+)
+
+// OpenAPI строит спецификацию HTTP транспорта из той же модели,
+// по которой генерируются маршруты и структуры запросов
+func (p templateParams) OpenAPI() (*openAPIDocument, error) {
+	s, err := p.generator.newSchemas(schemaRefPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %v", err)
+	}
+
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:   p.ServiceName + " API",
+			Version: strings.TrimPrefix(p.HTTPPathPrefix, "/api/"),
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+	}
+
+	s.envelope()
+	if p.Problem() {
+		s.problem()
+	}
+
+	for _, function := range p.Functions {
+		request, response, err := s.messages(function)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: method %s: %v", function.Name, err)
+		}
+		s.Components[function.Name+"Request"] = request
+
+		responses := p.errorResponses(len(function.Params) > 0)
+		switch {
+		case function.Stream:
+			s.Components[function.Name+"Response"] = response
+		case !p.Bare():
+			s.Components[function.Name+"Response"] = response
+			responses["200"] = &openAPIResponse{
+				Description: "Result of " + function.Name,
+				Content:     jsonContent(schemaRefPrefix + function.Name + "Response"),
+			}
+		case len(function.Values) == 0:
+			//Без результата ответ пустой
+			responses["204"] = &openAPIResponse{Description: function.Name + " succeeded"}
+		default:
+			//Ответ без обёртки - сам результат
+			responses["200"] = &openAPIResponse{
+				Description: "Result of " + function.Name,
+				Content:     map[string]openAPIMediaType{jsonContentType: {Schema: response.Properties["result"]}},
+			}
+		}
+		if function.Stream {
+			//Значения канала передаются событиями text/event-stream, ошибка вызова - обычным JSON ответом
+			value, err := s.Of(function.StreamType)
+			if err != nil {
+				return nil, fmt.Errorf("openapi: method %s: %v", function.Name, err)
+			}
+			responses["200"] = &openAPIResponse{
+				Description: "Server-Sent Events with a value of " + function.Name + " in each data field, the event end closes the stream",
+				Content: map[string]openAPIMediaType{
+					eventStreamContentType: {Schema: value},
+				},
+			}
+		}
+
+		path := p.HTTPPathPrefix + function.HTTPPath()
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openAPIOperation{}
+		}
+		operation := &openAPIOperation{
+			OperationID: function.Name,
+			Summary:     summary(function.Doc),
+			Description: function.Doc,
+			Responses:   responses,
+		}
+		//Методы без параметров вызываются GET без тела запроса
+		if len(function.Params) > 0 {
+			operation.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  jsonContent(schemaRefPrefix + function.Name + "Request"),
+			}
+		}
+		doc.Paths[path][strings.ToLower(function.HTTPMethod())] = operation
+	}
+
+	doc.Components.Schemas = s.Components
+	return doc, nil
+}
+
+// errorResponses описывает ответы с ошибкой: 400 при ошибке разбора тела запроса, если оно есть,
+// статусы из настроек для ошибок-сигналов и кодов AppError, остальные ошибки - default
+func (p templateParams) errorResponses(body bool) map[string]*openAPIResponse {
+	keys := map[int][]string{}
+	for _, status := range p.statuses() {
+		if status.Error != "" {
+			keys[status.Status] = append(keys[status.Status], status.Error)
+		} else {
+			keys[status.Status] = append(keys[status.Status], fmt.Sprintf("AppError code %d", status.Code))
+		}
+	}
+
+	responses := map[string]*openAPIResponse{
+		"default": {
+			Description: "Business or transport error, the AppError code is the status if it is an HTTP error status, otherwise 500",
+			Content:     p.errorContent(),
+		},
+	}
+	if body {
+		responses["400"] = &openAPIResponse{
+			Description: "Request body cannot be decoded",
+			Content:     p.errorContent(),
+		}
+	}
+	for status, names := range keys {
+		key := strconv.Itoa(status)
+		description := strings.Join(names, ", ")
+		if responses[key] != nil {
+			description = responses[key].Description + ", " + description
+		}
+		responses[key] = &openAPIResponse{
+			Description: description,
+			Content:     p.errorContent(),
+		}
+	}
+	return responses
+}
+
+// errorContent - содержимое ответа с ошибкой: Problem или GenericErrorResponse
+func (p templateParams) errorContent() map[string]openAPIMediaType {
+	if p.Problem() {
+		return map[string]openAPIMediaType{problemContentType: {Schema: &Schema{Ref: schemaRefPrefix + problemSchema}}}
+	}
+	return jsonContent(schemaRefPrefix + genericErrorResponse)
+}
+
+// problem описывает Problem, ответ с ошибкой по RFC 7807 с кодом и деталями AppError
+func (s *schemas) problem() {
+	s.Components[problemSchema] = &Schema{
+		Type:        "object",
+		Description: "RFC 7807 problem details, code and details are the AppError extension members",
+		Properties: map[string]*Schema{
+			"type":     {Type: "string", Format: "uri", Description: "urn:problem-type:<service>:<error> for declared errors, otherwise about:blank"},
+			"title":    {Type: "string", Description: "Message of the declared error or the HTTP status text"},
+			"status":   {Type: "integer", Format: "int32"},
+			"detail":   {Type: "string"},
+			"instance": {Type: "string", Format: "uri-reference", Description: "Request URI"},
+			"code":     {Type: "integer", Format: "int64"},
+			"details":  {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+		Required: []string{"type", "title", "status", "code"},
+	}
+}
+
+// envelope описывает AppError и GenericErrorResponse
+func (s *schemas) envelope() {
+	s.Components["AppError"] = &Schema{
+		Type:        "object",
+		Description: "Business or transport error with an application code",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Format: "int64"},
+			"message": {Type: "string"},
+			"details": {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+		Required: []string{"code", "message"},
+	}
+	s.Components[genericErrorResponse] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"error":   {Ref: s.refPrefix + "AppError"},
+		},
+		Required: []string{"success"},
+	}
+}
+
+// messages возвращает схемы структур запроса и ответа метода,
+// имена полей совпадают с тегами json в transport
+func (s *schemas) messages(function ServiceFunction) (request *Schema, response *Schema, err error) {
+	request = &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, param := range function.Params {
+		if request.Properties[param.JSON], err = s.Of(param.Type); err != nil {
+			return nil, nil, err
+		}
+		if !param.OmitEmpty {
+			request.Required = append(request.Required, param.JSON)
+		}
+	}
+
+	response = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"error":   {Ref: s.refPrefix + "AppError"},
+		},
+		Required: []string{"success"},
+	}
+	switch {
+	case function.Stream:
+		//Значения потока не входят в JSON ответа
+	case len(function.Values) == 0:
+	case len(function.Values) == 1:
+		if response.Properties["result"], err = s.Of(function.Values[0].Type); err != nil {
+			return nil, nil, err
+		}
+	default:
+		result := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for _, value := range function.Values {
+			if result.Properties[value.JSON], err = s.Of(value.Type); err != nil {
+				return nil, nil, err
+			}
+			if !value.OmitEmpty {
+				result.Required = append(result.Required, value.JSON)
+			}
+		}
+		response.Properties["result"] = result
+	}
+	return request, response, nil
+}
+
+func jsonContent(ref string) map[string]openAPIMediaType {
+	return map[string]openAPIMediaType{jsonContentType: {Schema: &Schema{Ref: ref}}}
+}
+
+// summary - первое предложение комментария метода
+func summary(doc string) string {
+	line, _, _ := strings.Cut(doc, "\n")
+	if idx := strings.Index(line, ". "); idx >= 0 {
+		line = line[:idx+1]
+	}
+	return line
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// protoScalar - соответствие скалярного типа Go типу protobuf
+type protoScalar struct {
+	Proto string // Тип в .proto
+	Go    string // Тип поля, который сгенерирует protoc-gen-go
+}
+
+var protoScalars = map[string]protoScalar{
+	"int":     {"int64", "int64"},
+	"int64":   {"int64", "int64"},
+	"int32":   {"int32", "int32"},
+	"int16":   {"int32", "int32"},
+	"int8":    {"int32", "int32"},
+	"uint":    {"uint64", "uint64"},
+	"uint64":  {"uint64", "uint64"},
+	"uint32":  {"uint32", "uint32"},
+	"uint16":  {"uint32", "uint32"},
+	"uint8":   {"uint32", "uint32"},
+	"byte":    {"uint32", "uint32"},
+	"rune":    {"int32", "int32"},
+	"float64": {"double", "float64"},
+	"float32": {"float", "float32"},
+	"string":  {"string", "string"},
+	"bool":    {"bool", "bool"},
+}
+
+// protoKind - способ переноса значения между Go и protobuf
+type protoKind int
+
+const (
+	protoDirect   protoKind = iota // Типы совпадают
+	protoCast                      // Скаляр с преобразованием типа
+	protoCastList                  // Срез скаляров с преобразованием каждого элемента
+	protoJSON                      // Всё остальное передаётся как JSON в поле bytes
+)
+
+// protoMapping описывает, как параметр представлен в .proto
+func (p parameter) protoMapping() (kind protoKind, proto string, elem protoScalar) {
+	if p.Type == "[]byte" || p.Type == "[]uint8" {
+		return protoDirect, "bytes", protoScalar{}
+	}
+	if scalar, ok := protoScalars[p.Type]; ok {
+		if scalar.Go == p.Type {
+			return protoDirect, scalar.Proto, scalar
+		}
+		return protoCast, scalar.Proto, scalar
+	}
+	if strings.HasPrefix(p.Type, "[]") {
+		goElem := strings.TrimPrefix(p.Type, "[]")
+		if scalar, ok := protoScalars[goElem]; ok {
+			if scalar.Go == goElem {
+				return protoDirect, "repeated " + scalar.Proto, scalar
+			}
+			return protoCastList, "repeated " + scalar.Proto, scalar
+		}
+	}
+	return protoJSON, "bytes", protoScalar{}
+}
+
+// ProtoType - тип поля в .proto
+func (p parameter) ProtoType() string {
+	_, proto, _ := p.protoMapping()
+	return proto
+}
+
+// ProtoName - имя поля в .proto в snake_case
+func (p parameter) ProtoName() string {
+	return snakeCase(p.Field)
+}
+
+// ProtoGoName - имя поля в структуре, сгенерированной protoc-gen-go
+func (p parameter) ProtoGoName() string {
+	return goCamelCase(p.ProtoName())
+}
+
+// DecodeProto возвращает операторы, переносящие значение src из сообщения protobuf в dst.
+// JSON поля при ошибке возвращают из функции nil, err
+func (p parameter) DecodeProto(dst, src string) string {
+	kind, _, _ := p.protoMapping()
+	switch kind {
+	case protoCast:
+		return fmt.Sprintf("%s = %s(%s)\n", dst, p.Type, src)
+	case protoCastList:
+		elem := strings.TrimPrefix(p.Type, "[]")
+		return fmt.Sprintf("%s = make(%s, len(%s))\nfor i, v := range %s {\n%s[i] = %s(v)\n}\n", dst, p.Type, src, src, dst, elem)
+	case protoJSON:
+		return fmt.Sprintf("if len(%s) > 0 {\nif err := json.Unmarshal(%s, &%s); err != nil {\nreturn nil, err\n}\n}\n", src, src, dst)
+	}
+	return fmt.Sprintf("%s = %s\n", dst, src)
+}
+
+// EncodeProto возвращает операторы, переносящие значение src в поле dst сообщения protobuf
+func (p parameter) EncodeProto(dst, src string) string {
+	kind, _, scalar := p.protoMapping()
+	switch kind {
+	case protoCast:
+		return fmt.Sprintf("%s = %s(%s)\n", dst, scalar.Go, src)
+	case protoCastList:
+		return fmt.Sprintf("%s = make([]%s, len(%s))\nfor i, v := range %s {\n%s[i] = %s(v)\n}\n", dst, scalar.Go, src, src, dst, scalar.Go)
+	case protoJSON:
+		return fmt.Sprintf("{\ndata, err := json.Marshal(%s)\nif err != nil {\nreturn nil, err\n}\n%s = data\n}\n", src, dst)
+	}
+	return fmt.Sprintf("%s = %s\n", dst, src)
+}
@@ -0,0 +1,98 @@
+package generator_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/pablogolobaro/servicegen/generator"
+	"go/format"
+	"path/filepath"
+	"strings"
+)
+
+// protoGoTypes - типы полей, которые protoc-gen-go генерирует для скаляров
+var protoGoTypes = map[string]string{
+	"int64":  "int64",
+	"int32":  "int32",
+	"uint64": "uint64",
+	"uint32": "uint32",
+	"double": "float64",
+	"float":  "float32",
+	"string": "string",
+	"bool":   "bool",
+	"bytes":  "[]byte",
+}
+
+// protoStubs подменяет результат protoc для проверки типов: по каждому
+// сгенерированному .proto собирает пакет pb с сообщениями, интерфейсом сервера
+// и функцией регистрации с теми же именами, что дают protoc-gen-go и protoc-gen-go-grpc
+func protoStubs(files []generator.File) ([]generator.File, error) {
+	var stubs []generator.File
+	for _, file := range files {
+		if filepath.Ext(file.Path) != ".proto" {
+			continue
+		}
+		content, err := protoStub(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file.Path, err)
+		}
+		stubs = append(stubs, generator.File{
+			Path:    filepath.Join(filepath.Dir(file.Path), "service.pb.go"),
+			Package: file.Package,
+			Content: content,
+		})
+	}
+	return stubs, nil
+}
+
+func protoStub(proto []byte) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("package pb\n\nimport (\n\"context\"\n\"google.golang.org/grpc\"\n)\n\n")
+
+	scanner := bufio.NewScanner(bytes.NewReader(proto))
+	for scanner.Scan() {
+		fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ";", "", "=", " ").Replace(scanner.Text()))
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(fields[0], "//"):
+		case fields[0] == "message":
+			fmt.Fprintf(&b, "type %s struct {\n", fields[1])
+		case fields[0] == "service":
+			name := fields[1]
+			fmt.Fprintf(&b, "type %sServer interface {\nmustEmbedUnimplemented%sServer()\n", name, name)
+			var rpcs [][]string
+			for scanner.Scan() {
+				rpc := strings.Fields(strings.NewReplacer("(", " ", ")", " ", ";", "").Replace(scanner.Text()))
+				if len(rpc) == 0 || rpc[0] != "rpc" {
+					break
+				}
+				rpcs = append(rpcs, rpc)
+				fmt.Fprintf(&b, "%s(context.Context, *%s) (*%s, error)\n", rpc[1], rpc[2], rpc[4])
+			}
+			fmt.Fprintf(&b, "}\n\ntype Unimplemented%sServer struct{}\n\n", name)
+			fmt.Fprintf(&b, "func (Unimplemented%sServer) mustEmbedUnimplemented%sServer() {}\n\n", name, name)
+			for _, rpc := range rpcs {
+				fmt.Fprintf(&b, "func (Unimplemented%sServer) %s(context.Context, *%s) (*%s, error) { return nil, nil }\n\n", name, rpc[1], rpc[2], rpc[4])
+			}
+			fmt.Fprintf(&b, "func Register%sServer(s grpc.ServiceRegistrar, srv %sServer) {}\n\n", name, name)
+		case fields[0] == "}":
+			b.WriteString("}\n\n")
+		case len(fields) >= 3 && fields[0] != "syntax" && fields[0] != "package" && fields[0] != "option":
+			repeated := fields[0] == "repeated"
+			if repeated {
+				fields = fields[1:]
+			}
+			goType, ok := protoGoTypes[fields[0]]
+			if !ok {
+				goType = "*" + fields[0]
+			}
+			if repeated {
+				goType = "[]" + goType
+			}
+			fmt.Fprintf(&b, "%s %s\n", generator.GoCamelCase(fields[1]), goType)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
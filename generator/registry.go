@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// Artifact описывает один выходной файл генератора
+type Artifact interface {
+	// Name - уникальное имя артефакта
+	Name() string
+	// PackageName - имя пакета выходного файла
+	PackageName(r ServiceGenerator) string
+	// OutputPath - путь выходного файла относительно пакета сервиса
+	OutputPath(r ServiceGenerator) string
+	// TemplateName - имя шаблона в цепочке templates.Loader
+	TemplateName() string
+	// Enabled - нужно ли генерировать артефакт для сервиса
+	Enabled(r ServiceGenerator) bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Artifact
+)
+
+// Register добавляет артефакт в реестр.
+// Вызывается из init() встроенных и сторонних пакетов, повторное имя - паника
+func Register(a Artifact) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if a == nil {
+		panic("generator: Register artifact is nil")
+	}
+	for _, registered := range registry {
+		if registered.Name() == a.Name() {
+			panic(fmt.Sprintf("generator: Register called twice for artifact %s", a.Name()))
+		}
+	}
+	registry = append(registry, a)
+}
+
+// Artifacts возвращает зарегистрированные артефакты в порядке регистрации
+func Artifacts() []Artifact {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Artifact(nil), registry...)
+}
+
+// FileArtifact - артефакт из одного файла, сгенерированного по шаблону.
+// Файлы с расширением, отличным от .go, записываются без разбора и форматирования
+type FileArtifact struct {
+	ID       string                      // Имя артефакта
+	Package  string                      // Имя пакета, пустое - пакет сервиса
+	Dir      string                      // Каталог относительно пакета сервиса
+	File     string                      // Имя файла без суффикса _gen.go или полное имя с расширением
+	Template string                      // Имя шаблона
+	Option   string                      // Опция аннотации, включающая артефакт, пустая - всегда
+	When     func(ServiceGenerator) bool // Произвольное условие вместо Option
+}
+
+func (a FileArtifact) Name() string {
+	return a.ID
+}
+
+func (a FileArtifact) PackageName(r ServiceGenerator) string {
+	if a.Package == "" {
+		return r.ServicePackageName
+	}
+	return a.Package
+}
+
+func (a FileArtifact) OutputPath(r ServiceGenerator) string {
+	if filepath.Ext(a.File) != "" {
+		return filepath.Join(a.Dir, a.File)
+	}
+	return filepath.Join(a.Dir, a.File) + "_gen.go"
+}
+
+func (a FileArtifact) TemplateName() string {
+	return a.Template
+}
+
+func (a FileArtifact) Enabled(r ServiceGenerator) bool {
+	if a.When != nil {
+		return a.When(r)
+	}
+	return a.Option == "" || r.Annotation.Has(a.Option)
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultHTTPRouter - роутер HTTP транспорта по умолчанию
+const defaultHTTPRouter = "echo"
+
+// httpRouters - роутеры, для которых генерируются регистрация маршрутов и запуск сервера
+var httpRouters = []string{"echo", "chi", "stdlib", "gin"}
+
+// httpRouter возвращает роутер: опция аннотации router, затем настройки, затем умолчание
+func (r ServiceGenerator) httpRouter() string {
+	if v := r.Annotation.Value("router"); v != "" {
+		return v
+	}
+	if r.Config.HTTP.Router != "" {
+		return r.Config.HTTP.Router
+	}
+	return defaultHTTPRouter
+}
+
+// checkHTTPRouter проверяет, что для роутера есть шаблоны
+func (r ServiceGenerator) checkHTTPRouter() error {
+	router := r.httpRouter()
+	for _, known := range httpRouters {
+		if router == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown HTTP router %q, use %s", router, strings.Join(httpRouters, ", "))
+}
+
+// Router - роутер, на котором регистрируются маршруты HTTP транспорта и JSON-RPC
+func (p templateParams) Router() string {
+	return p.generator.httpRouter()
+}
+
+// GETFunctions - методы без параметров, вызываемые GET запросом без тела
+func (p templateParams) GETFunctions() []ServiceFunction {
+	var functions []ServiceFunction
+	for _, function := range p.Functions {
+		if len(function.Params) == 0 {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"github.com/pablogolobaro/servicegen/templates"
+	"github.com/pablogolobaro/servicegen/utils"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/ast/inspector"
+	"os"
+	"path/filepath"
+)
+
+// Options - параметры запуска генератора
+type Options struct {
+	Sources   []string          // Файлы с интерфейсами, помеченными //servicegen:service
+	Config    Config            // Настройки генерации
+	Templates *templates.Loader // Цепочка поиска шаблонов, по умолчанию из Config.Templates
+	FS        FS                // Куда записать результат, nil - только вернуть в Result
+}
+
+// File - один сгенерированный файл
+type File struct {
+	Path     string // Путь относительно текущего каталога, рядом с исходным файлом
+	Package  string // Имя пакета
+	Artifact string // Имя артефакта, породившего файл
+	Service  string // Имя интерфейса сервиса
+	Content  []byte // Исходный код
+}
+
+// Result - результат генерации
+type Result struct {
+	Files []File
+}
+
+// Run разбирает исходные файлы, генерирует артефакты всех найденных сервисов,
+// при Config.Verify проверяет их типы и, если задана opts.FS, записывает их туда
+func Run(ctx context.Context, opts Options) (Result, error) {
+	var result Result
+	//Ошибки копим до конца запуска, чтобы показать все сразу
+	var diagnostics Diagnostics
+
+	fset := token.NewFileSet()
+
+	for _, source := range opts.Sources {
+		tasks, err := opts.services(fset, source)
+		if err != nil {
+			diagnostics = append(diagnostics, toDiagnostics(err, source)...)
+			continue
+		}
+
+		//Запускаем список заданий генерации
+		for _, task := range tasks {
+			if err := task.Check(); err != nil {
+				diagnostics = append(diagnostics, toDiagnostics(err, source)...)
+				continue
+			}
+
+			//Для каждого задания вызываем генератор артефакта
+			for _, artifact := range task.EnabledArtifacts() {
+				if err := ctx.Err(); err != nil {
+					return result, err
+				}
+
+				path := task.OutputPath(artifact)
+
+				content, err := task.Generate(artifact)
+				if err != nil {
+					diagnostics = append(diagnostics, toDiagnostics(fmt.Errorf("generate %s: %w", artifact.Name(), err), path)...)
+					continue
+				}
+
+				result.Files = append(result.Files, File{
+					Path:     path,
+					Package:  artifact.PackageName(task),
+					Artifact: artifact.Name(),
+					Service:  task.TypeSpec.Name.Name,
+					Content:  content,
+				})
+			}
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		return result, diagnostics
+	}
+
+	//При ошибках типов ничего не записываем
+	if opts.Config.Verify {
+		if err := Verify(ctx, result.Files); err != nil {
+			return result, err
+		}
+	}
+
+	if opts.FS != nil {
+		for _, file := range result.Files {
+			if err := opts.FS.WriteFile(file.Path, file.Content); err != nil {
+				return result, fmt.Errorf("generate file %s: %v", file.Path, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// services разбирает исходный файл и выделяет задания генерации
+func (opts Options) services(fset *token.FileSet, source string) ([]ServiceGenerator, error) {
+	//Разбираем целевой файл в AST
+	astInFile, err := parser.ParseFile(
+		fset,
+		source,
+		nil,
+		//Нас интересуют комментарии
+		parser.ParseComments,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleRoot, err := utils.FindModuleRoot(filepath.Dir(source))
+	if err != nil {
+		return nil, fmt.Errorf("module root of %s: %v", source, err)
+	}
+
+	module := opts.Config.Module
+	if module == "" {
+		module, err = modulePath(moduleRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	packagePath, err := utils.ImportPath(module, moduleRoot, filepath.Dir(source))
+	if err != nil {
+		return nil, err
+	}
+
+	//Шаблоны проекта перекрывают пользовательские и встроенные
+	loader := opts.Templates
+	if loader == nil {
+		templatesDir := opts.Config.Templates
+		if templatesDir == "" {
+			templatesDir = filepath.Join(moduleRoot, templates.ProjectDir)
+		}
+		loader = templates.NewLoader(templatesDir)
+	}
+
+	servicePackageName := astInFile.Name.Name
+	//Для выбора интересных нам деклараций
+	//используем Inspector из golang.org/x/tools/go/ast/inspector
+	i := inspector.New([]*ast.File{astInFile})
+	//Подготовим фильтр для этого инспектора
+	iFilter := []ast.Node{
+		//Нас интересуют декларации
+		&ast.GenDecl{},
+	}
+	//Выделяем список заданий генерации
+	var genTasks []ServiceGenerator
+
+	//Запускаем инспектор с подготовленным фильтром
+	//и литералом фильтрующей функции
+	i.Nodes(iFilter, func(node ast.Node, push bool) (proceed bool) {
+		genDecl := node.(*ast.GenDecl)
+		//Код без комментариев не нужен,
+		if genDecl.Doc == nil {
+			return false
+		}
+		//интересуют спецификации типов,
+		typeSpec, ok := genDecl.Specs[0].(*ast.TypeSpec)
+		if !ok {
+			return false
+		}
+		//а конкретно интерфейсы
+		interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+		if !ok {
+			return false
+		}
+		//Из оставшегося
+		for _, comment := range genDecl.Doc.List {
+			//выделяем структуры, помеченные комментарием servicegen:service,
+			annotation, ok := ParseAnnotation(comment.Text)
+			if !ok {
+				continue
+			}
+			//и добавляем в список заданий генерации
+			genTasks = append(genTasks, ServiceGenerator{
+				FileIdent:          astInFile.Name,
+				TypeSpec:           typeSpec,
+				Methods:            interfaceType.Methods.List,
+				PackagePath:        packagePath,
+				ServicePackageName: servicePackageName,
+				ModuleName:         module,
+				Imports:            astInFile.Imports,
+				Comments:           astInFile.Comments,
+				Fset:               fset,
+				Source:             source,
+				Annotation:         annotation,
+				Config:             opts.Config,
+				Templates:          loader,
+			})
+		}
+		return false
+	})
+
+	return genTasks, nil
+}
+
+// modulePath читает имя модуля из go.mod
+func modulePath(moduleRoot string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(moduleRoot, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("read go.mod: %v", err)
+	}
+
+	module := modfile.ModulePath(content)
+	if module == "" {
+		return "", fmt.Errorf("module path not found in %s", filepath.Join(moduleRoot, "go.mod"))
+	}
+	return module, nil
+}
@@ -0,0 +1,274 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Schema - JSON Schema типа в подмножестве, общем для OpenAPI 3.0 и AsyncAPI
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// basicSchemas - схемы встроенных типов Go
+var basicSchemas = map[string]Schema{
+	"bool":    {Type: "boolean"},
+	"string":  {Type: "string"},
+	"int":     {Type: "integer", Format: "int64"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"int16":   {Type: "integer", Format: "int32"},
+	"int8":    {Type: "integer", Format: "int32"},
+	"rune":    {Type: "integer", Format: "int32"},
+	"uint":    {Type: "integer", Format: "int64"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"uint32":  {Type: "integer", Format: "int32"},
+	"uint16":  {Type: "integer", Format: "int32"},
+	"uint8":   {Type: "integer", Format: "int32"},
+	"byte":    {Type: "integer", Format: "int32"},
+	"float64": {Type: "number", Format: "double"},
+	"float32": {Type: "number", Format: "float"},
+	"any":     {},
+	"error":   {Type: "string"},
+}
+
+// externalSchemas - схемы распространённых типов других пакетов,
+// остальные внешние типы описываются произвольным значением
+var externalSchemas = map[string]Schema{
+	"time.Time":       {Type: "string", Format: "date-time"},
+	"time.Duration":   {Type: "integer", Format: "int64", Description: "nanoseconds"},
+	"json.RawMessage": {},
+}
+
+// schemas строит схемы типов из сигнатур сервиса.
+// Типы пакета сервиса ищутся в файлах его каталога и попадают в Components
+type schemas struct {
+	pkg        string                   // Имя пакета сервиса, которым квалифицированы типы
+	decls      map[string]*ast.TypeSpec // Объявления типов пакета сервиса
+	Components map[string]*Schema       // Именованные схемы, на которые ссылаются $ref
+	refPrefix  string                   // Префикс ссылок, например #/components/schemas/
+}
+
+// newSchemas разбирает объявления типов в каталоге исходного файла сервиса
+func (r ServiceGenerator) newSchemas(refPrefix string) (*schemas, error) {
+	s := &schemas{
+		pkg:        r.ServicePackageName,
+		decls:      map[string]*ast.TypeSpec{},
+		Components: map[string]*Schema{},
+		refPrefix:  refPrefix,
+	}
+
+	dir := filepath.Dir(r.Source)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name != r.ServicePackageName {
+			continue
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+					typeSpec.Doc = genDecl.Doc
+				}
+				s.decls[typeSpec.Name.Name] = typeSpec
+			}
+		}
+	}
+	return s, nil
+}
+
+// Of возвращает схему типа, записанного строкой, как в parameter.Type
+func (s *schemas) Of(typ string) (*Schema, error) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil, fmt.Errorf("parse type %s: %v", typ, err)
+	}
+	return s.expr(expr), nil
+}
+
+func (s *schemas) expr(expr ast.Expr) *Schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if basic, ok := basicSchemas[t.Name]; ok {
+			return &basic
+		}
+		//Неквалифицированное имя встречается внутри объявлений пакета сервиса
+		return s.named(t.Name)
+	case *ast.SelectorExpr:
+		pkg, _ := t.X.(*ast.Ident)
+		if pkg != nil && pkg.Name == s.pkg {
+			return s.named(t.Sel.Name)
+		}
+		name := t.Sel.Name
+		if pkg != nil {
+			name = pkg.Name + "." + name
+		}
+		if external, ok := externalSchemas[name]; ok {
+			return &external
+		}
+		return &Schema{Description: name}
+	case *ast.StarExpr:
+		ret := s.expr(t.X)
+		if ret.Ref != "" {
+			//В OpenAPI 3.0 соседние с $ref ключи игнорируются
+			return ret
+		}
+		ret.Nullable = true
+		return ret
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.expr(t.Elt)}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: s.expr(t.Value)}
+	case *ast.StructType:
+		return s.structType(t)
+	}
+	return &Schema{}
+}
+
+// named возвращает ссылку на тип пакета сервиса и описывает его в Components
+func (s *schemas) named(name string) *Schema {
+	ref := &Schema{Ref: s.refPrefix + name}
+	if _, ok := s.Components[name]; ok {
+		return ref
+	}
+	spec, ok := s.decls[name]
+	if !ok {
+		return &Schema{Description: s.pkg + "." + name}
+	}
+
+	//Регистрируем заранее, чтобы рекурсивные типы не зацикливались
+	component := &Schema{}
+	s.Components[name] = component
+	*component = *s.expr(spec.Type)
+	if spec.Doc != nil {
+		component.Description = strings.TrimSpace(spec.Doc.Text())
+	}
+	return ref
+}
+
+func (s *schemas) structType(t *ast.StructType) *Schema {
+	ret := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range t.Fields.List {
+		name, omitempty, skip := jsonTag(field)
+		if skip {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			//Встроенная структура пакета сервиса раскрывается, как это делает encoding/json
+			if embedded := s.embedded(field.Type); embedded != nil && name == "" {
+				for key, value := range embedded.Properties {
+					ret.Properties[key] = value
+				}
+				ret.Required = append(ret.Required, embedded.Required...)
+				continue
+			}
+			if name == "" {
+				name = embeddedName(field.Type)
+			}
+			s.property(ret, name, field, omitempty)
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			fieldName := name
+			if fieldName == "" {
+				fieldName = ident.Name
+			}
+			s.property(ret, fieldName, field, omitempty)
+		}
+	}
+	return ret
+}
+
+func (s *schemas) property(schema *Schema, name string, field *ast.Field, omitempty bool) {
+	property := s.expr(field.Type)
+	if field.Doc != nil && property.Ref == "" {
+		property.Description = strings.TrimSpace(field.Doc.Text())
+	}
+	schema.Properties[name] = property
+	if !omitempty {
+		schema.Required = append(schema.Required, name)
+	}
+}
+
+// embedded возвращает схему встроенной структуры пакета сервиса
+func (s *schemas) embedded(expr ast.Expr) *Schema {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	spec, ok := s.decls[ident.Name]
+	if !ok {
+		return nil
+	}
+	structType, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	return s.structType(structType)
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// jsonTag разбирает тег json поля
+func jsonTag(field *ast.Field) (name string, omitempty bool, skip bool) {
+	if field.Tag == nil {
+		return "", false, false
+	}
+	tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+options+",", ",omitempty,"), false
+}
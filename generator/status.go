@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// httpStatusOption - начало опций аннотации http.status.<код или ошибка>=<статус>
+const httpStatusOption = "http.status."
+
+// Форматы ответов HTTP транспорта с ошибкой
+const (
+	httpErrorsEnvelope = "envelope" // GenericErrorResponse, по умолчанию
+	httpErrorsProblem  = "problem"  // application/problem+json по RFC 7807
+)
+
+// httpErrors возвращает формат ответов с ошибкой: опция аннотации http.errors, затем настройки, затем умолчание
+func (r ServiceGenerator) httpErrors() string {
+	if v := r.Annotation.Value("http.errors"); v != "" {
+		return v
+	}
+	if r.Config.HTTP.Errors != "" {
+		return r.Config.HTTP.Errors
+	}
+	return httpErrorsEnvelope
+}
+
+// checkHTTPErrors проверяет формат ответов с ошибкой
+func (r ServiceGenerator) checkHTTPErrors() error {
+	if format := r.httpErrors(); format != httpErrorsEnvelope && format != httpErrorsProblem {
+		return fmt.Errorf("unknown HTTP error format %q, use %s or %s", format, httpErrorsEnvelope, httpErrorsProblem)
+	}
+	return nil
+}
+
+// Problem - ошибки HTTP транспорта передаются в формате application/problem+json
+func (p templateParams) Problem() bool {
+	return p.generator.httpErrors() == httpErrorsProblem
+}
+
+// httpStatus - статус HTTP ответа для ошибки-сигнала сервиса или кода AppError
+type httpStatus struct {
+	Error  string // Имя ошибки-сигнала пакета сервиса, пустое - статус кода
+	Code   int    // Код AppError
+	Status int    // Статус HTTP ответа
+}
+
+// httpStatuses возвращает статусы ошибок: статусы объявленных ошибок, поверх них настройки http.status,
+// поверх них опции аннотации. Сначала ошибки-сигналы по имени, затем коды по возрастанию
+func (r ServiceGenerator) httpStatuses() ([]httpStatus, error) {
+	statuses := map[string]string{}
+	domainErrors, _ := r.domainErrors()
+	for _, e := range domainErrors {
+		if e.HTTP != 0 {
+			statuses[e.Name] = strconv.Itoa(e.HTTP)
+		}
+	}
+	for key, status := range r.Config.HTTP.Status {
+		statuses[key] = strconv.Itoa(status)
+	}
+	for option, status := range r.Annotation.Options {
+		if key, ok := strings.CutPrefix(option, httpStatusOption); ok {
+			statuses[key] = status
+		}
+	}
+
+	var ret []httpStatus
+	for key, value := range statuses {
+		status, err := strconv.Atoi(value)
+		if err != nil || !errorStatus(status) {
+			return nil, fmt.Errorf("invalid HTTP status %q of %s, use 400-599", value, key)
+		}
+		if code, err := strconv.Atoi(key); err == nil {
+			ret = append(ret, httpStatus{Code: code, Status: status})
+			continue
+		}
+		if !token.IsIdentifier(key) || !token.IsExported(key) {
+			return nil, fmt.Errorf("invalid HTTP status key %q, use an AppError code or an exported error variable", key)
+		}
+		ret = append(ret, httpStatus{Error: key, Status: status})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if (ret[i].Error == "") != (ret[j].Error == "") {
+			return ret[i].Error != ""
+		}
+		if ret[i].Error != ret[j].Error {
+			return ret[i].Error < ret[j].Error
+		}
+		return ret[i].Code < ret[j].Code
+	})
+	return ret, nil
+}
+
+// errorStatus сообщает, является ли число известным статусом HTTP ошибки 4xx или 5xx
+func errorStatus(status int) bool {
+	return status >= 400 && status <= 599 && http.StatusText(status) != ""
+}
+
+// unmappedErrors возвращает ошибки-сигналы, код которых не является статусом HTTP ошибки, а статус
+// не задан ни по имени, ни по коду: без статуса транспорт отвечал бы на них 500
+func unmappedErrors(errors []DomainError, statuses []httpStatus) []error {
+	mapped := map[string]bool{}
+	for _, status := range statuses {
+		if status.Error != "" {
+			mapped[status.Error] = true
+			continue
+		}
+		mapped[strconv.Itoa(status.Code)] = true
+	}
+	var ret []error
+	for _, e := range errors {
+		if errorStatus(e.Code) || mapped[e.Name] || mapped[strconv.Itoa(e.Code)] {
+			continue
+		}
+		ret = append(ret, fmt.Errorf("error %s: code %d is not an HTTP error status, set http= or %s%s", e.Name, e.Code, httpStatusOption, e.Name))
+	}
+	return ret
+}
+
+// undeclaredErrors возвращает статусы, заданные по имени ошибки, которой нет среди объявленных:
+// шаблоны ссылаются на переменную пакета сервиса, и без объявления код не компилируется
+func undeclaredErrors(errors []DomainError, statuses []httpStatus) []error {
+	declared := map[string]bool{}
+	for _, e := range errors {
+		declared[e.Name] = true
+	}
+	var ret []error
+	for _, status := range statuses {
+		if status.Error == "" || declared[status.Error] {
+			continue
+		}
+		ret = append(ret, fmt.Errorf("HTTP status of undeclared error %s, declare it in //%s or the config", status.Error, ErrorsMarker))
+	}
+	return ret
+}
+
+// HTTPErrorStatuses - статусы ошибок-сигналов, проверяются errors.Is
+func (p templateParams) HTTPErrorStatuses() []httpStatus {
+	var ret []httpStatus
+	for _, status := range p.statuses() {
+		if status.Error != "" {
+			ret = append(ret, status)
+		}
+	}
+	return ret
+}
+
+// HTTPCodeStatuses - статусы кодов AppError
+func (p templateParams) HTTPCodeStatuses() []httpStatus {
+	var ret []httpStatus
+	for _, status := range p.statuses() {
+		if status.Error == "" {
+			ret = append(ret, status)
+		}
+	}
+	return ret
+}
+
+// statuses - статусы ошибок сервиса, ошибка настроек уже выдана диагностикой convertFunctions
+func (p templateParams) statuses() []httpStatus {
+	ret, _ := p.generator.httpStatuses()
+	return ret
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// streamPrefix - начало типа результата потокового метода
+const streamPrefix = "<-chan "
+
+// streamlessTransports - транспорты без потоковой передачи в порядке проверки.
+// HTTP передаёт значения канала через Server-Sent Events, NATS - отдельными сообщениями в inbox запроса
+var streamlessTransports = []string{"grpc", "kafka", "amqp", "jsonrpc"}
+
+// streamElement возвращает тип элемента канала, если результат метода - канал только для чтения
+func streamElement(typ string) (string, bool) {
+	if !strings.HasPrefix(typ, streamPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(typ, streamPrefix), true
+}
+
+// checkStreamTransports проверяет, что все транспорты сервиса поддерживают потоковые методы
+func (r ServiceGenerator) checkStreamTransports() error {
+	for _, option := range streamlessTransports {
+		if r.Annotation.Has(option) {
+			return fmt.Errorf("streaming methods are not supported by the %s transport", option)
+		}
+	}
+	return nil
+}
+
+// usesStreams - у сервиса есть потоковые методы, для них генерируются отдельные тесты
+func (r ServiceGenerator) usesStreams() bool {
+	for _, method := range r.Methods {
+		funcType, ok := method.Type.(*ast.FuncType)
+		if !ok || funcType.Results == nil || len(funcType.Results.List) == 0 {
+			continue
+		}
+		if ch, ok := funcType.Results.List[0].Type.(*ast.ChanType); ok && ch.Dir == ast.RECV {
+			return true
+		}
+	}
+	return false
+}
+
+// Streams - потоковые методы сервиса
+func (p templateParams) Streams() []ServiceFunction {
+	var ret []ServiceFunction
+	for _, function := range p.Functions {
+		if function.Stream {
+			ret = append(ret, function)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,20 @@
+package calc
+
+import (
+	"context"
+)
+
+//go:generate servicegen -mod github.com/pablogolobaro/servicegen
+
+//servicegen:errors
+//ErrNegativeAddArgs "add arguments cannot be negative" code=333 http=400
+//ErrTestRetryable "error to test retry" code=444 http=503 retryable
+
+//servicegen:service http nats logging tracing
+type Calc interface {
+	//servicegen:validate a>=0 b>=0
+	Add(ctx context.Context, a, b int) (int, error)
+	//servicegen:validate User required,max=64
+	//servicegen:validate Mail required
+	Erase(ctx context.Context, User string, Mail string) (uint, error)
+}
@@ -0,0 +1,33 @@
+package catalog
+
+import (
+	"context"
+	"time"
+)
+
+// Item is a catalog entry.
+type Item struct {
+	ID    string
+	Tags  []string
+	Price float64
+}
+
+// Filter narrows Find results.
+type Filter map[string]string
+
+//servicegen:errors
+//ErrNotFound "item not found" code=404 grpc=NotFound
+//ErrExists "item already exists" code=1001 http=409 grpc=AlreadyExists
+//ErrBusy "catalog is busy" code=1002 retryable grpc=Unavailable
+
+//servicegen:service http grpc nats kafka logging tracing tests kafka.commit=sync router=gin http.status.ErrBusy=503
+type Catalog interface {
+	// Find returns items by ids. Items not matching the filter are skipped.
+	//servicegen:validate ids required,max=100
+	Find(ctx context.Context, ids []string, filter Filter) ([]*Item, error)
+	// Put stores the item for ttl and returns the stored copy.
+	//servicegen:validate ttl>=1s ttl<=24h
+	Put(ctx context.Context, item Item, ttl time.Duration) (*Item, error)
+	//servicegen:kafka events=-
+	Since(context.Context, time.Time) (map[string][]Item, error)
+}
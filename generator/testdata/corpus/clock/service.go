@@ -0,0 +1,7 @@
+package clock
+
+//servicegen:service http amqp jsonrpc logging tests json.naming=snake json.omitempty
+type Clock interface {
+	Now() (int64, error)
+	Format(layout string, unixSeconds int64) (string, error)
+}
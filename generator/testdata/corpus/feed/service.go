@@ -0,0 +1,25 @@
+package feed
+
+import "context"
+
+// Event is a change of a feed topic.
+type Event struct {
+	Topic string
+	Seq   int64
+	Body  string
+}
+
+//servicegen:errors
+//ErrUnknownTopic "unknown topic" code=404
+//ErrStorage "event storage failed" code=500
+
+//servicegen:service http nats logging tracing tests router=chi http.errors=problem
+type Feed interface {
+	// Publish appends an event to the topic and returns its sequence number.
+	//servicegen:validate topic required,max=64
+	Publish(ctx context.Context, topic string, body string) (int64, error)
+	// Watch streams the events of the topics matching filter until ctx is cancelled.
+	Watch(ctx context.Context, filter string) (<-chan Event, error)
+	// Ticks streams the sequence numbers from 1 to n.
+	Ticks(n int) (<-chan int64, error)
+}
@@ -0,0 +1,20 @@
+package stats
+
+import "context"
+
+//servicegen:errors
+//ErrOverloaded "too many values to record" code=503 retryable grpc=ResourceExhausted
+
+//servicegen:service http grpc nats logging tracing tests nats.queue=stats-workers router=stdlib http.response=bare json.naming=camel
+type Stats interface {
+	MinMax(ctx context.Context, values []float64) (min float64, max float64, err error)
+	// Split splits s around sep and returns the parts with their count.
+	//servicegen:json sep=separator,omitempty
+	Split(ctx context.Context, s string, sep string) (parts []string, partCount int, err error)
+	// Reset clears the collected values on every replica.
+	//servicegen:nats subject=stats.admin.reset queue=-
+	Reset(ctx context.Context) error
+	// Record adds a value to the statistics.
+	//servicegen:nats jetstream
+	Record(ctx context.Context, value float64) error
+}
@@ -0,0 +1,33 @@
+//go:build tools
+
+package testdata
+
+// Зависимости сгенерированного кода.
+// Держим их в go.mod модуля testdata, чтобы golden тесты могли проверять типы результата генерации,
+// а модуль генератора и пользователи generator.Run их не наследовали
+import (
+	_ "github.com/IBM/sarama"
+	_ "github.com/IBM/sarama/mocks"
+	_ "github.com/fatih/color"
+	_ "github.com/gin-gonic/gin"
+	_ "github.com/go-chi/chi/v5"
+	_ "github.com/go-kit/kit/transport/amqp"
+	_ "github.com/go-kit/kit/transport/grpc"
+	_ "github.com/go-kit/kit/transport/http"
+	_ "github.com/go-kit/kit/transport/nats"
+	_ "github.com/kyokomi/emoji"
+	_ "github.com/labstack/echo-contrib/prometheus"
+	_ "github.com/labstack/echo/v4"
+	_ "github.com/nats-io/nats-server/v2/server"
+	_ "github.com/nats-io/nats.go"
+	_ "github.com/prometheus/client_golang/prometheus"
+	_ "github.com/prometheus/client_golang/prometheus/promhttp"
+	_ "github.com/spf13/cobra"
+	_ "github.com/spf13/viper"
+	_ "github.com/streadway/amqp"
+	_ "go.opentelemetry.io/contrib/instrumentation/github.com/go-kit/kit/otelkit"
+	_ "go.opentelemetry.io/otel/exporters/jaeger"
+	_ "go.opentelemetry.io/otel/sdk/trace"
+	_ "go.uber.org/zap"
+	_ "google.golang.org/grpc"
+)
@@ -0,0 +1,15 @@
+package errorsconflict
+
+import "context"
+
+//servicegen:errors
+//ErrMissing "order is missing" code=404
+//ErrMissing "order is missing again" code=404
+//ErrGone "order is gone" code=410 grpc=Gone
+//ErrLost order is lost code=1
+//ErrStale "order is stale" code=404
+
+//servicegen:service http
+type Orders interface {
+	Place(ctx context.Context) error
+}
@@ -0,0 +1,8 @@
+package errorsformat
+
+import "context"
+
+//servicegen:service http http.errors=xml
+type Orders interface {
+	Place(ctx context.Context) error
+}
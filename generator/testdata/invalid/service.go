@@ -0,0 +1,13 @@
+package invalid
+
+import "context"
+
+type Event struct{}
+
+//servicegen:service http
+type Watcher interface {
+	Watch(ctx context.Context, filter string) (chan Event, error)
+	Count(ctx context.Context) int
+	Apply(ctx context.Context, fn func(Event) bool) error
+	Tags(ctx context.Context, tags ...string) error
+}
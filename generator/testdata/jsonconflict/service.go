@@ -0,0 +1,9 @@
+package jsonconflict
+
+import "context"
+
+//servicegen:service http
+type Orders interface {
+	//servicegen:json id=name
+	Place(ctx context.Context, id string, name string) error
+}
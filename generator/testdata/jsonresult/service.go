@@ -0,0 +1,9 @@
+package jsonresult
+
+import "context"
+
+//servicegen:service http
+type Users interface {
+	//servicegen:json name=query total=count
+	Count(ctx context.Context, name string) (total int, err error)
+}
@@ -0,0 +1,8 @@
+package jsonrpcconflict
+
+import "context"
+
+//servicegen:service http jsonrpc jsonrpc.path=/api/v1/rpc
+type Orders interface {
+	Place(ctx context.Context) error
+}
@@ -0,0 +1,11 @@
+package jsonside
+
+import "context"
+
+//servicegen:service http
+type Users interface {
+	//servicegen:json request.name=query response.name=title
+	Search(ctx context.Context, name string) (Name string, Count int, err error)
+	//servicegen:json name=title
+	Find(ctx context.Context, name string) (Name string, err error)
+}
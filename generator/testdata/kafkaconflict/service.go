@@ -0,0 +1,12 @@
+package kafkaconflict
+
+import "context"
+
+//servicegen:service kafka kafka.commit=never
+type Orders interface {
+	//servicegen:kafka topic=orders.v1.place.events
+	Create(ctx context.Context) error
+	Place(ctx context.Context) error
+	//servicegen:kafka topic=orders/{method}
+	Cancel(ctx context.Context) error
+}
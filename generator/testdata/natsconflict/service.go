@@ -0,0 +1,14 @@
+package natsconflict
+
+import "context"
+
+//servicegen:service nats nats.subject=jobs.{method}
+type Jobs interface {
+	//servicegen:nats subject=jobs.run
+	Start(ctx context.Context) error
+	Run(ctx context.Context) error
+	//servicegen:nats subject=jobs.{id}
+	Get(ctx context.Context) error
+	//servicegen:nats jetstream
+	Count(ctx context.Context) (int, error)
+}
@@ -0,0 +1,8 @@
+package routerconflict
+
+import "context"
+
+//servicegen:service http router=mux
+type Orders interface {
+	Place(ctx context.Context) error
+}
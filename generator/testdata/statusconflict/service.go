@@ -0,0 +1,8 @@
+package statusconflict
+
+import "context"
+
+//servicegen:service http http.status.ErrNotFound=302
+type Orders interface {
+	Place(ctx context.Context) error
+}
@@ -0,0 +1,9 @@
+package streamconflict
+
+import "context"
+
+//servicegen:service http grpc
+type Feed interface {
+	Watch(ctx context.Context) (<-chan int, error)
+	Follow(ctx context.Context) (<-chan int, int, error)
+}
@@ -0,0 +1,11 @@
+package undeclaredstatus
+
+import "context"
+
+//servicegen:errors
+//ErrGone "order is gone" code=410
+
+//servicegen:service http http.status.ErrGone=404 http.status.ErrMissing=404
+type Orders interface {
+	Place(ctx context.Context) error
+}
@@ -0,0 +1,9 @@
+package unknowndirective
+
+import "context"
+
+//servicegen:service nats
+type Orders interface {
+	//servicegen:natsfoo subject=orders.place
+	Place(ctx context.Context) error
+}
@@ -0,0 +1,15 @@
+package unmappedstatus
+
+import "context"
+
+//servicegen:errors
+//ErrGone "order is gone" code=410
+//ErrMapped "order is mapped" code=333 http=409
+//ErrByCode "order is mapped by code" code=334
+//ErrUnknown "unknown status" code=444
+//ErrLow "low code" code=7
+
+//servicegen:service http http.status.334=409
+type Orders interface {
+	Place(ctx context.Context) error
+}
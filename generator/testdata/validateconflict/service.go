@@ -0,0 +1,9 @@
+package validateconflict
+
+import "context"
+
+//servicegen:service http
+type Orders interface {
+	//servicegen:validate count required,min=one
+	Place(ctx context.Context, count int) error
+}
@@ -0,0 +1,13 @@
+package validaterange
+
+import "context"
+
+//servicegen:service http
+type Orders interface {
+	//servicegen:validate small>=-128 small<=1000
+	Place(ctx context.Context, small int8) error
+	//servicegen:validate count>=-1 total<=18446744073709551615
+	Count(ctx context.Context, count uint, total uint64) error
+	//servicegen:validate ratio<=1e40
+	Rate(ctx context.Context, ratio float32) error
+}
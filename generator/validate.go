@@ -0,0 +1,301 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidateMarker - директива метода с правилами проверки полей запроса:
+//
+//	//servicegen:validate a>=0 b>=0
+//	//servicegen:validate user required,max=64
+const ValidateMarker = "servicegen:validate"
+
+// validationError - ошибка-сигнал проверки запроса, если блок //servicegen:errors не объявляет свою
+var validationError = DomainError{
+	Name:    "ErrValidation",
+	Message: "request validation failed",
+	Code:    http.StatusUnprocessableEntity,
+	HTTP:    http.StatusUnprocessableEntity,
+	GRPC:    "InvalidArgument",
+}
+
+// numberTypes - числовые типы, к которым применимы сравнения
+var numberTypes = map[string]bool{
+	"int": true, "int64": true, "int32": true, "int16": true, "int8": true, "rune": true,
+	"uint": true, "uint64": true, "uint32": true, "uint16": true, "uint8": true, "byte": true,
+	"float64": true, "float32": true, "time.Duration": true,
+}
+
+// comparisons - правила в форме сравнения, a>=0: оператор и правило
+var comparisons = []struct {
+	op   string
+	rule string
+}{
+	{">=", "min"},
+	{"<=", "max"},
+	{">", "gt"},
+	{"<", "lt"},
+}
+
+// validation - проверки поля запроса, первая не пройденная попадает в детали ошибки
+type validation struct {
+	Field  string  // Поле запроса
+	JSON   string  // Ключ деталей ошибки - JSON имя поля
+	Checks []check // Проверки по порядку
+}
+
+// check - условие ошибки поля на Go и её описание
+type check struct {
+	Cond    string // Условие, при котором поле не проходит проверку
+	Message string // Текст в деталях ошибки
+}
+
+// hasValidation сообщает, есть ли у методов сервиса правила проверки
+func (r ServiceGenerator) hasValidation() bool {
+	for _, method := range r.Methods {
+		if len(methodRules(method)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// methodRules возвращает правила всех директив //servicegen:validate метода
+func methodRules(method *ast.Field) []string {
+	if method.Doc == nil {
+		return nil
+	}
+	var ret []string
+	for _, comment := range method.Doc.List {
+		if rest, ok := directiveText(comment.Text, ValidateMarker); ok {
+			ret = append(ret, strings.Fields(rest)...)
+		}
+	}
+	return ret
+}
+
+// validations разбирает правила метода: сравнения a>=0 и пары поле - правила через запятую,
+// required, min=, max=, gt=, lt=
+func validations(method *ast.Field, params []parameter) ([]validation, error) {
+	var (
+		ret   []validation
+		index = map[string]int{}
+	)
+	add := func(name string, rules []string) error {
+		p := findField(params, name)
+		if p == nil {
+			return fmt.Errorf("validate: unknown argument %q", name)
+		}
+		i, ok := index[p.Field]
+		if !ok {
+			i = len(ret)
+			index[p.Field] = i
+			ret = append(ret, validation{Field: p.Field, JSON: p.JSON})
+		}
+		for _, rule := range rules {
+			name, value, _ := strings.Cut(rule, "=")
+			c, err := newCheck(*p, name, value)
+			if err != nil {
+				return fmt.Errorf("validate: %s: %v", p.Field, err)
+			}
+			//Обязательность проверяется первой, остальные правила имеют смысл для заполненного поля
+			if name == "required" {
+				ret[i].Checks = append([]check{c}, ret[i].Checks...)
+				continue
+			}
+			ret[i].Checks = append(ret[i].Checks, c)
+		}
+		return nil
+	}
+
+	rules := methodRules(method)
+	for i := 0; i < len(rules); i++ {
+		token := rules[i]
+		if name, rule, ok := comparison(token); ok {
+			if err := add(name, []string{rule}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if i+1 == len(rules) {
+			return nil, fmt.Errorf("validate: argument %s has no rules", token)
+		}
+		i++
+		if err := add(token, strings.Split(rules[i], ",")); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// comparison разбирает правило a>=0 в поле и правило min=0
+func comparison(token string) (string, string, bool) {
+	idx := strings.IndexAny(token, "<>")
+	if idx <= 0 {
+		return "", "", false
+	}
+	for _, c := range comparisons {
+		if strings.HasPrefix(token[idx:], c.op) {
+			return token[:idx], c.rule + "=" + token[idx+len(c.op):], true
+		}
+	}
+	return "", "", false
+}
+
+// newCheck строит проверку поля p по правилу и его значению
+func newCheck(p parameter, rule, value string) (check, error) {
+	field := "r." + p.Field
+	kind := typeKind(p.Type)
+	if rule == "required" {
+		if value != "" {
+			return check{}, fmt.Errorf("rule required takes no value")
+		}
+		switch kind {
+		case "number":
+			return check{Cond: field + " == 0", Message: "is required"}, nil
+		case "string":
+			return check{Cond: field + ` == ""`, Message: "is required"}, nil
+		case "len":
+			return check{Cond: "len(" + field + ") == 0", Message: "is required"}, nil
+		case "pointer":
+			return check{Cond: field + " == nil", Message: "is required"}, nil
+		case "time":
+			return check{Cond: field + ".IsZero()", Message: "is required"}, nil
+		}
+		return check{}, fmt.Errorf("rule required is not supported for type %s", p.Type)
+	}
+
+	var op, message string
+	switch rule {
+	case "min":
+		op, message = "<", "must be at least "
+	case "max":
+		op, message = ">", "must be at most "
+	case "gt":
+		op, message = "<=", "must be greater than "
+	case "lt":
+		op, message = ">=", "must be less than "
+	default:
+		return check{}, fmt.Errorf("unknown rule %q", rule)
+	}
+
+	switch kind {
+	case "number":
+		literal, err := numberLiteral(p.Type, value)
+		if err != nil {
+			return check{}, fmt.Errorf("rule %s: %v", rule, err)
+		}
+		return check{Cond: field + " " + op + " " + literal, Message: message + value}, nil
+	case "string", "len":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return check{}, fmt.Errorf("rule %s: invalid length %q", rule, value)
+		}
+		if rule != "min" && rule != "max" {
+			return check{}, fmt.Errorf("rule %s is not supported for type %s, use min or max", rule, p.Type)
+		}
+		if kind == "string" {
+			return check{Cond: "utf8.RuneCountInString(" + field + ") " + op + " " + value, Message: message + value + " characters long"}, nil
+		}
+		return check{Cond: "len(" + field + ") " + op + " " + value, Message: strings.Replace(message, "be", "have", 1) + value + " items"}, nil
+	}
+	return check{}, fmt.Errorf("rule %s is not supported for type %s", rule, p.Type)
+}
+
+// typeKind - вид типа для правил: number, string, len (срезы и словари), pointer, time или пустой
+func typeKind(typ string) string {
+	switch {
+	case numberTypes[typ]:
+		return "number"
+	case typ == "string":
+		return "string"
+	case typ == "time.Time":
+		return "time"
+	case strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return "len"
+	case strings.HasPrefix(typ, "*"):
+		return "pointer"
+	}
+	return ""
+}
+
+// numberLiteral проверяет значение правила числового поля и его диапазон для типа поля,
+// длительности записываются как 1s
+func numberLiteral(typ, value string) (string, error) {
+	var err error
+	switch {
+	case typ == "time.Duration":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid duration %q", value)
+		}
+		return strconv.FormatInt(int64(d), 10), nil
+	case strings.HasPrefix(typ, "float"):
+		if _, err = strconv.ParseFloat(value, bitSize(typ)); err != nil && !errors.Is(err, strconv.ErrRange) {
+			return "", fmt.Errorf("invalid number %q", value)
+		}
+	case strings.HasPrefix(typ, "uint"), typ == "byte":
+		//Отрицательная граница - не синтаксическая ошибка, а выход за диапазон беззнакового поля
+		digits, negative := strings.CutPrefix(value, "-")
+		if _, err = strconv.ParseUint(digits, 10, bitSize(typ)); err == nil && negative {
+			err = strconv.ErrRange
+		}
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return "", fmt.Errorf("invalid unsigned integer %q", value)
+		}
+	default:
+		if _, err = strconv.ParseInt(value, 10, bitSize(typ)); err != nil && !errors.Is(err, strconv.ErrRange) {
+			return "", fmt.Errorf("invalid integer %q", value)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s is out of range of %s", value, typ)
+	}
+	return value, nil
+}
+
+// bitSize - размер числового типа в битах, int и uint - по платформе генератора
+func bitSize(typ string) int {
+	switch typ {
+	case "int8", "uint8", "byte":
+		return 8
+	case "int16", "uint16":
+		return 16
+	case "int32", "uint32", "rune", "float32":
+		return 32
+	case "int", "uint":
+		return strconv.IntSize
+	}
+	return 64
+}
+
+// RequiredFields - JSON имя первого обязательного поля запроса по имени метода,
+// пустой запрос таких методов не проходит проверку
+func (p templateParams) RequiredFields() map[string]string {
+	ret := map[string]string{}
+	for _, f := range p.Functions {
+		for _, v := range f.Validations {
+			if len(v.Checks) > 0 && v.Checks[0].Message == "is required" {
+				ret[f.Name] = v.JSON
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// ValidationCode - код статуса gRPC ошибки проверки запроса, пустой - ошибка передаётся в поле Error ответа
+func (p templateParams) ValidationCode() string {
+	for _, e := range p.Errors() {
+		if e.Name == validationError.Name {
+			return e.GRPC
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"github.com/pablogolobaro/servicegen/utils"
+	"go/token"
+	"golang.org/x/tools/go/packages"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// Verify загружает сгенерированные пакеты поверх исходников модуля
+// через go/types, используя зависимости модуля, и проверяет типы.
+// Файлы на диск не пишутся, ошибки возвращаются как Diagnostics
+func Verify(ctx context.Context, files []File) error {
+	//Файлы могут относиться к разным модулям, каждый проверяем отдельно
+	byRoot := map[string][]File{}
+	for _, file := range files {
+		if filepath.Ext(file.Path) != ".go" {
+			continue
+		}
+		root, err := utils.FindModuleRoot(filepath.Dir(file.Path))
+		if err != nil {
+			return fmt.Errorf("verify %s: %v", file.Path, err)
+		}
+		byRoot[root] = append(byRoot[root], file)
+	}
+
+	var roots []string
+	for root := range byRoot {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+
+	var diagnostics Diagnostics
+	for _, root := range roots {
+		moduleDiagnostics, err := verifyModule(ctx, root, byRoot[root])
+		if err != nil {
+			return err
+		}
+		diagnostics = append(diagnostics, moduleDiagnostics...)
+	}
+
+	if len(diagnostics) > 0 {
+		return diagnostics
+	}
+	return nil
+}
+
+func verifyModule(ctx context.Context, root string, files []File) (Diagnostics, error) {
+	module, err := modulePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	//Сгенерированные файлы подкладываются поверх файловой системы,
+	//абсолютные пути заменяем в сообщениях обратно на пути результата
+	overlay := map[string][]byte{}
+	paths := map[string]string{}
+	patterns := map[string]bool{}
+	for _, file := range files {
+		abs, err := filepath.Abs(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		overlay[abs] = file.Content
+		paths[abs] = file.Path
+
+		rel, err := filepath.Rel(root, filepath.Dir(abs))
+		if err != nil {
+			return nil, err
+		}
+		patterns[path.Join(module, filepath.ToSlash(rel))] = true
+	}
+
+	var list []string
+	for pattern := range patterns {
+		list = append(list, pattern)
+	}
+	sort.Strings(list)
+
+	pkgs, err := packages.Load(&packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     root,
+		Overlay: overlay,
+	}, list...)
+	if err != nil {
+		return nil, fmt.Errorf("verify: load packages: %v", err)
+	}
+
+	var diagnostics Diagnostics
+	for _, pkg := range pkgs {
+		//Ошибки go list дублируют ошибки типов с путями временных файлов
+		errs := pkg.Errors
+		var typeErrs []packages.Error
+		for _, e := range errs {
+			if e.Kind == packages.TypeError {
+				typeErrs = append(typeErrs, e)
+			}
+		}
+		if len(typeErrs) > 0 {
+			errs = typeErrs
+		}
+
+		for _, e := range errs {
+			if e.Pos == "" || e.Pos == "-" {
+				diagnostics = append(diagnostics, newDiagnostic(token.Position{}, "", fmt.Sprintf("%s: %s", pkg.PkgPath, e.Msg)))
+				continue
+			}
+			pos := parsePosition(e.Pos)
+			if rel, ok := paths[pos.Filename]; ok {
+				pos.Filename = rel
+			}
+			diagnostics = append(diagnostics, newDiagnostic(pos, "", e.Msg))
+		}
+	}
+	return diagnostics, nil
+}
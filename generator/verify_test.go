@@ -0,0 +1,47 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"github.com/pablogolobaro/servicegen/generator"
+	"github.com/pablogolobaro/servicegen/templates"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVerifyReportsTypeErrors(t *testing.T) {
+	broken := fstest.MapFS{
+		templates.ImplementationTemplate: {Data: []byte(`package implementation
+
+func New{{ .ServiceName }}Service() int {
+	return "not an int"
+}
+`)},
+	}
+
+	fs := generator.NewMemFS()
+	_, err := generator.Run(context.Background(), generator.Options{
+		Sources:   []string{corpus["clock"]},
+		Config:    generator.Config{Verify: true},
+		Templates: templates.NewLoaderFS(broken, templates.Defaults()),
+		FS:        fs,
+	})
+
+	var diagnostics generator.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		t.Fatalf("want Diagnostics, got %v", err)
+	}
+	//Ошибка в самом шаблоне и несовпадение конструктора с вызовом в общей сборке сервиса
+	for _, want := range []string{
+		"implementation/implementation_gen.go:4:9: cannot use",
+		"cmd/service_gen.go:35:39: too many arguments in call to implementation.NewClockService",
+	} {
+		if !strings.Contains(diagnostics.Error(), want) {
+			t.Errorf("want %q in:\n%v", want, diagnostics)
+		}
+	}
+	if names := fs.Names(); len(names) != 0 {
+		t.Errorf("nothing should be written on type errors, got %v", names)
+	}
+}
@@ -1,146 +1,133 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/jinzhu/gorm"
 	"github.com/pablogolobaro/servicegen/generator"
+	"github.com/pablogolobaro/servicegen/templates"
 	"github.com/pablogolobaro/servicegen/utils"
-	"go/ast"
-	"go/parser"
-	"go/token"
-	"golang.org/x/tools/go/ast/inspector"
 	"log"
 	"os"
-	"strings"
+	"path/filepath"
 )
 
 func main() {
-	//Аллокация результирующих деревьев разбора
-	mod := flag.String("mod", "github.com/pablogolobaro/servicegen", "Module name of generate source service")
-
-	flag.Parse()
-
-	if *mod == "." {
-		panic("No module name is provided")
+	//Подкоманда работы с шаблонами
+	if len(os.Args) > 1 && os.Args[1] == "templates" {
+		templatesCommand(os.Args[2:])
+		return
 	}
 
-	fmt.Println(*mod)
+	mod := flag.String("mod", "", "Module name of generate source service (default from go.mod)")
+	templatesDir := flag.String("templates", "", "Project templates directory (default <module root>/"+templates.ProjectDir+")")
+	configPath := flag.String("config", "", "Config file (default <module root>/"+generator.ConfigFileName+" if exists)")
+	verify := flag.Bool("verify", false, "Type-check generated code before writing it")
+	tests := flag.Bool("tests", false, "Generate tests of the transports into the service packages")
+	jsonOutput := flag.Bool("json", false, "Print diagnostics as JSON")
+
+	flag.Parse()
 
 	_ = gorm.DB{}
 
-	//Цель генерации передаётся переменной окружения
-	path := os.Getenv("GOFILE")
-	if path == "" {
-		path = "./services/calc/service.go"
+	//Цели генерации передаются аргументами или переменной окружения go generate
+	sources := flag.Args()
+	if len(sources) == 0 {
+		path := os.Getenv("GOFILE")
+		if path == "" {
+			path = "./services/calc/service.go"
+		}
+		sources = []string{path}
 	}
 
-	packagePath := utils.GetPackagePath(*mod)
-
-	fmt.Println(packagePath)
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	//Флаги перекрывают файл настроек
+	if *mod != "" {
+		cfg.Module = *mod
+	}
+	if *templatesDir != "" {
+		cfg.Templates = *templatesDir
+	}
+	if *verify {
+		cfg.Verify = true
+	}
+	if *tests {
+		cfg.Tests = true
+	}
 
-	//Разбираем целевой файл в AST
-	astInFile, err := parser.ParseFile(
-		token.NewFileSet(),
-		path,
-		nil,
-		//Нас интересуют комментарии
-		parser.ParseComments,
-	)
+	result, err := generator.Run(context.Background(), generator.Options{
+		Sources: sources,
+		Config:  cfg,
+		FS:      generator.DirFS("."),
+	})
 	if err != nil {
-		log.Fatalf("parse file: %v", err)
+		//Позиционные ошибки печатаем как компилятор или JSON для редакторов
+		var diagnostics generator.Diagnostics
+		if !errors.As(err, &diagnostics) {
+			log.Fatalf("generate: %v", err)
+		}
+		if *jsonOutput {
+			diagnostics.WriteJSON(os.Stdout)
+		} else {
+			fmt.Fprintln(os.Stderr, diagnostics.Error())
+		}
+		os.Exit(1)
 	}
 
-	servicePackageName := astInFile.Name.Name
-	//Для выбора интересных нам деклараций
-	//используем Inspector из golang.org/x/tools/go/ast/inspector
-	i := inspector.New([]*ast.File{astInFile})
-	//Подготовим фильтр для этого инспектора
-	iFilter := []ast.Node{
-		//Нас интересуют декларации
-		&ast.GenDecl{},
+	for _, file := range result.Files {
+		fmt.Println(file.Path)
 	}
-	//Выделяем список заданий генерации
-	var genTasks []generator.ServiceGenerator
+}
 
-	//Запускаем инспектор с подготовленным фильтром
-	//и литералом фильтрующей функции
-	i.Nodes(iFilter, func(node ast.Node, push bool) (proceed bool) {
-		genDecl := node.(*ast.GenDecl)
-		//Код без комментариев не нужен,
-		if genDecl.Doc == nil {
-			return false
-		}
-		//интересуют спецификации типов,
-		typeSpec, ok := genDecl.Specs[0].(*ast.TypeSpec)
-		if !ok {
-			return false
+// loadConfig читает файл настроек, по умолчанию необязательный servicegen.yaml в корне модуля
+func loadConfig(path string) (generator.Config, error) {
+	if path == "" {
+		root, err := utils.FindModuleRoot(".")
+		if err != nil {
+			return generator.Config{}, nil
 		}
-		//а конкретно интерфейсы
-		interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
-		if !ok {
-			return false
+		path = filepath.Join(root, generator.ConfigFileName)
+		if _, err := os.Stat(path); err != nil {
+			return generator.Config{}, nil
 		}
-		//Из оставшегося
-		for _, comment := range genDecl.Doc.List {
-
-			var gen generator.ServiceGenerator
+	}
+	return generator.LoadConfig(path)
+}
 
-			//выделяем структуры, помеченные комментарием servicegen:service,
-			if strings.Contains(comment.Text, "servicegen:service") {
-				//и добавляем в список заданий генерации
-				gen = generator.ServiceGenerator{
-					FileIdent:          astInFile.Name,
-					TypeSpec:           typeSpec,
-					Methods:            interfaceType.Methods.List,
-					PackagePath:        packagePath,
-					ServicePackageName: servicePackageName,
-					ModuleName:         *mod,
-					OutFiles: map[string]*ast.File{
-						generator.ImplementationPackage: {Name: &ast.Ident{Name: generator.ImplementationPackage}},
-						generator.TransportPackage:      {Name: &ast.Ident{Name: generator.TransportPackage}},
-						generator.RootFilename:          {Name: &ast.Ident{Name: generator.CmdPackage}},
-						generator.ConfigPackage:         {Name: &ast.Ident{Name: generator.ConfigPackage}},
-						generator.OtelTracingPackage:    {Name: &ast.Ident{Name: generator.OtelTracingPackage}},
-						generator.ErrorFileName:         {Name: &ast.Ident{Name: servicePackageName}},
-					},
-				}
-			}
-			if strings.Contains(comment.Text, "http") {
-				gen.OutFiles[generator.HttpFileName] = &ast.File{Name: &ast.Ident{Name: generator.HttpPackage}}
-				gen.OutFiles[generator.HttpRunFilename] = &ast.File{Name: &ast.Ident{Name: generator.CmdPackage}}
+// templatesCommand обрабатывает "servicegen templates export"
+func templatesCommand(args []string) {
+	if len(args) == 0 || args[0] != "export" {
+		log.Fatalf("usage: servicegen templates export [-dir path] [-force]")
+	}
 
-			}
-			if strings.Contains(comment.Text, "nats") {
-				gen.OutFiles[generator.NatsFileName] = &ast.File{Name: &ast.Ident{Name: generator.NatsPackage}}
-			}
-			if strings.Contains(comment.Text, "logging") {
-				gen.OutFiles[generator.LoggingFileName] = &ast.File{Name: &ast.Ident{Name: generator.MiddlewarePackage}}
-			}
-			if strings.Contains(comment.Text, "tracing") {
-				gen.OutFiles[generator.TracingFileName] = &ast.File{Name: &ast.Ident{Name: generator.MiddlewarePackage}}
-			}
+	fs := flag.NewFlagSet("templates export", flag.ExitOnError)
+	dir := fs.String("dir", "", "Target directory (default <module root>/"+templates.ProjectDir+")")
+	force := fs.Bool("force", false, "Overwrite existing templates")
+	fs.Parse(args[1:])
 
-			genTasks = append(genTasks, gen)
-		}
-		return false
-	})
+	if *dir == "" {
+		*dir = projectTemplatesDir()
+	}
 
-	//Запускаем список заданий генерации
-	for _, task := range genTasks {
-		//Для каждого задания вызываем написанный нами генератор
-		//как метод этого задания
-		//Сгенерированные декларации помещаются в результирующее дерево разбора
-		for fileName, outFile := range task.OutFiles {
-			err = task.Generate(outFile, fileName)
-			if err != nil {
-				log.Fatalf("generate: %v", err)
-			}
+	written, err := templates.Export(*dir, *force)
+	for _, path := range written {
+		fmt.Println(path)
+	}
+	if err != nil {
+		log.Fatalf("templates: %v", err)
+	}
+}
 
-			err := task.GenerateFile(outFile, fileName)
-			if err != nil {
-				log.Fatalf("generate file: %v", err)
-			}
-		}
+// projectTemplatesDir возвращает каталог шаблонов в корне текущего модуля
+func projectTemplatesDir() string {
+	root, err := utils.FindModuleRoot(".")
+	if err != nil {
+		root = "."
 	}
+	return filepath.Join(root, templates.ProjectDir)
 }
@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Export выгружает встроенные шаблоны в каталог dir как отправную точку для правок.
+// Существующие файлы перезаписываются только при force
+func Export(dir string, force bool) ([]string, error) {
+	var written []string
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create dir: %v", err)
+	}
+
+	err := fs.WalkDir(Defaults(), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		target := filepath.Join(dir, path)
+		if _, err := os.Stat(target); err == nil && !force {
+			return fmt.Errorf("%s already exists", target)
+		}
+
+		content, err := fs.ReadFile(Defaults(), path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+		written = append(written, target)
+		return nil
+	})
+	if err != nil {
+		return written, fmt.Errorf("export templates: %v", err)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,232 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Имена встроенных шаблонов
+const (
+	ImplementationTemplate  = "implementation.tmpl"
+	TransportTemplate       = "transport.tmpl"
+	HttpTemplate            = "http.tmpl"
+	ClientCmdTemplate       = "clientcmd.tmpl"
+	ClientCmdTestTemplate   = "clientcmdtest.tmpl"
+	HttpRunTemplate         = "httprun.tmpl"
+	HttpClientTemplate      = "httpclient.tmpl"
+	HttpTestTemplate        = "httptest.tmpl"
+	HttpStreamTestTemplate  = "httpstreamtest.tmpl"
+	NatsTemplate            = "nats.tmpl"
+	NatsClientTemplate      = "natsclient.tmpl"
+	NatsClientTestTemplate  = "natsclienttest.tmpl"
+	JetStreamTemplate       = "jetstream.tmpl"
+	JetStreamTestTemplate   = "jetstreamtest.tmpl"
+	KafkaTemplate           = "kafka.tmpl"
+	AmqpTemplate            = "amqp.tmpl"
+	AmqpClientTemplate      = "amqpclient.tmpl"
+	AmqpTestTemplate        = "amqptest.tmpl"
+	JsonRpcTemplate         = "jsonrpc.tmpl"
+	JsonRpcClientTemplate   = "jsonrpcclient.tmpl"
+	JsonRpcTestTemplate     = "jsonrpctest.tmpl"
+	KafkaTestTemplate       = "kafkatest.tmpl"
+	ConfigTemplate          = "config.tmpl"
+	RootTemplate            = "root.tmpl"
+	TracingTemplate         = "tracing.tmpl"
+	LoggingTemplate         = "logging.tmpl"
+	InstrumentationTemplate = "instrumentation.tmpl"
+	ErrorTemplate           = "error.tmpl"
+	ProtoTemplate           = "proto.tmpl"
+	GrpcTemplate            = "grpc.tmpl"
+	GrpcRunTemplate         = "grpcrun.tmpl"
+	GrpcTestTemplate        = "grpctest.tmpl"
+	ServiceTemplate         = "service.tmpl"
+	OpenAPIYAMLTemplate     = "openapi.yaml.tmpl"
+	OpenAPIJSONTemplate     = "openapi.json.tmpl"
+	AsyncAPIYAMLTemplate    = "asyncapi.yaml.tmpl"
+	AsyncAPIJSONTemplate    = "asyncapi.json.tmpl"
+	TestingTemplate         = "testing.tmpl"
+)
+
+// Partials - шаблоны с общими частями, их {{ define }} доступны всем шаблонам через {{ template }}
+var Partials = []string{TestingTemplate}
+
+// ProjectDir - каталог шаблонов проекта относительно корня модуля
+const ProjectDir = ".servicegen/templates"
+
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+var LowerCaseFunc = func(str string) string {
+	return strings.ToLower(str)
+}
+var UpperFirstLetter = func(str string) string {
+	r, size := utf8.DecodeRuneInString(str)
+	if size == 0 {
+		return str
+	}
+	return string(unicode.ToUpper(r)) + str[size:]
+}
+
+// Funcs - набор функций, доступных во всех шаблонах
+var Funcs = template.FuncMap{
+	"lower":              LowerCaseFunc,
+	"first_letter_upper": UpperFirstLetter,
+	"add":                func(a, b int) int { return a + b },
+	"yaml":               toYAML,
+	"json":               toJSON,
+}
+
+// toYAML печатает значение документом YAML
+func toYAML(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// toJSON печатает значение JSON с отступами
+func toJSON(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Defaults возвращает встроенные шаблоны
+func Defaults() fs.FS {
+	sub, err := fs.Sub(defaults, "defaults")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []fs.FS
+)
+
+// Register добавляет источник шаблонов стороннего пакета.
+// Такие шаблоны перекрывают встроенные, но не проектные и пользовательские
+func Register(fsys fs.FS) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	plugins = append(plugins, fsys)
+}
+
+// UserDir возвращает пользовательский каталог шаблонов
+func UserDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "servicegen", "templates"), nil
+}
+
+// Loader ищет шаблон по цепочке источников: первый найденный побеждает
+type Loader struct {
+	sources []fs.FS
+	mu      sync.Mutex
+	cache   map[string]*template.Template
+}
+
+// NewLoader собирает цепочку: каталог проекта, каталог пользователя,
+// шаблоны сторонних пакетов, встроенные шаблоны. Пустой projectDir пропускается
+func NewLoader(projectDir string) *Loader {
+	var sources []fs.FS
+	if projectDir != "" {
+		sources = append(sources, os.DirFS(projectDir))
+	}
+	if userDir, err := UserDir(); err == nil {
+		sources = append(sources, os.DirFS(userDir))
+	}
+
+	pluginsMu.RLock()
+	sources = append(sources, plugins...)
+	pluginsMu.RUnlock()
+
+	sources = append(sources, Defaults())
+	return NewLoaderFS(sources...)
+}
+
+// NewLoaderFS собирает цепочку из произвольных источников
+func NewLoaderFS(sources ...fs.FS) *Loader {
+	return &Loader{
+		sources: sources,
+		cache:   map[string]*template.Template{},
+	}
+}
+
+// Lookup возвращает разобранный шаблон с именем name
+func (l *Loader) Lookup(name string) (*template.Template, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t, ok := l.cache[name]; ok {
+		return t, nil
+	}
+
+	content, err := l.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	//Общие части разбираются первыми, чтобы шаблон мог переопределить их
+	t := template.New(name).Funcs(Funcs)
+	for _, partial := range Partials {
+		if partial == name {
+			continue
+		}
+		text, err := l.read(partial)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := t.New(partial).Parse(string(text)); err != nil {
+			return nil, fmt.Errorf("parse template %s: %v", partial, err)
+		}
+	}
+	if _, err := t.Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("parse template %s: %v", name, err)
+	}
+	l.cache[name] = t
+	return t, nil
+}
+
+// read возвращает содержимое шаблона name из первого источника, где он есть
+func (l *Loader) read(name string) ([]byte, error) {
+	for _, source := range l.sources {
+		content, err := fs.ReadFile(source, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read template %s: %v", name, err)
+		}
+		return content, nil
+	}
+	return nil, fmt.Errorf("template %s not found: %w", name, fs.ErrNotExist)
+}
@@ -11,25 +11,6 @@ import (
 	"strings"
 )
 
-func GetPackagePath(mod string) string {
-	var packageDir string
-	cwd, _ := os.Getwd()
-
-	modParts := strings.Split(mod, "/")
-	lastModPart := modParts[len(modParts)-1]
-
-	splitedCwd := strings.Split(cwd, lastModPart)
-	packageDir = splitedCwd[len(splitedCwd)-1]
-
-	fmt.Println(packageDir)
-
-	packagePath := filepath.Join(mod, packageDir)
-
-	packagePath = cleanPath(packagePath)
-
-	return packagePath
-}
-
 func cleanPath(dirPath string) string {
 	dirPath = strings.Replace(dirPath, "\\", "/", -1)
 	return dirPath
@@ -44,3 +25,37 @@ func Expr2string(expr ast.Expr) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// FindModuleRoot поднимается от dir вверх до каталога с go.mod
+func FindModuleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found")
+		}
+		dir = parent
+	}
+}
+
+// ImportPath возвращает путь импорта пакета из каталога dir модуля mod с корнем moduleRoot
+func ImportPath(mod, moduleRoot, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(moduleRoot, absDir)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return mod, nil
+	}
+	return cleanPath(filepath.Join(mod, rel)), nil
+}